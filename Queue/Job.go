@@ -0,0 +1,47 @@
+package Queue
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusReserved  Status = "reserved"
+	StatusCompleted Status = "completed"
+	StatusDead      Status = "dead"
+)
+
+type Job struct {
+	Id          uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;uniqueIndex"`
+	Queue       string     `json:"queue" gorm:"index:idx_jobs_claimable,priority:1;not null"`
+	Status      Status     `json:"status" gorm:"index:idx_jobs_claimable,priority:2;not null"`
+	Payload     string     `json:"payload" gorm:"not null"`
+	Attempts    int        `json:"attempts" gorm:"not null"`
+	MaxAttempts int        `json:"max_attempts" gorm:"not null"`
+	LastError   string     `json:"last_error"`
+	AvailableAt time.Time  `json:"available_at" gorm:"index:idx_jobs_claimable,priority:3;not null"`
+	ReservedAt  *time.Time `json:"reserved_at"`
+	Version     int        `json:"version" repository:"version"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"index;not null"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"not null"`
+}
+
+// TableName
+// All queues share a single table
+func (job *Job) TableName() string {
+	return "jobs"
+}
+
+// Decode
+// Decodes the payload of the job into a value
+func Decode[P any](job *Job) (P, error) {
+	var payload P
+
+	err := json.Unmarshal([]byte(job.Payload), &payload)
+
+	return payload, err
+}
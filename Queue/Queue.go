@@ -0,0 +1,355 @@
+package Queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nbj/go-collections/Collection"
+	"github.com/nbj/go-repository/Repository"
+	"math"
+	"time"
+)
+
+// claimBatchSize
+// The number of candidate jobs considered per claim. Losing the
+// race for one candidate moves on to the next one
+const claimBatchSize = 10
+
+type Options struct {
+	// The time a claimed job stays invisible to other workers
+	// before it is considered abandoned and claimed again
+	VisibilityTimeout time.Duration
+
+	// The number of attempts before a job is dead-lettered
+	MaxAttempts int
+
+	// The delay before a failed job is retried, given the
+	// number of attempts made so far
+	Backoff func(attempts int) time.Duration
+
+	// The configuration used instead of the default configuration
+	Config *Repository.Config
+}
+
+type Queue[P any] struct {
+	name    string
+	options Options
+}
+
+type Reservation[P any] struct {
+	Job     *Job
+	Payload P
+}
+
+// Of
+// Named constructor for creating instances of a queue
+func Of[P any](name string, options ...Options) *Queue[P] {
+	var queue Queue[P]
+	queue.name = name
+
+	if len(options) > 0 {
+		queue.options = options[0]
+	}
+
+	queue.applyDefaultOptions()
+
+	return &queue
+}
+
+// ExponentialBackoff
+// Doubles the delay, starting from base, for every attempt made
+func ExponentialBackoff(base time.Duration) func(attempts int) time.Duration {
+	return func(attempts int) time.Duration {
+		return base * time.Duration(math.Pow(2, float64(attempts-1)))
+	}
+}
+
+// Name
+// Returns the name of the queue
+func (queue *Queue[P]) Name() string {
+	return queue.name
+}
+
+// Enqueue
+// Pushes a payload onto the queue, available right away
+func (queue *Queue[P]) Enqueue(payload P) *Job {
+	return queue.Later(0, payload)
+}
+
+// Later
+// Pushes a payload onto the queue, available after a delay
+func (queue *Queue[P]) Later(delay time.Duration, payload P) *Job {
+	encoded, err := json.Marshal(payload)
+
+	if err != nil {
+		panic("Queue[Enqueue]: " + err.Error())
+	}
+
+	id, _ := uuid.NewV7()
+
+	return queue.repository().Create(Job{
+		Id:          id,
+		Queue:       queue.name,
+		Status:      StatusPending,
+		Payload:     string(encoded),
+		MaxAttempts: queue.options.MaxAttempts,
+		AvailableAt: now().Add(delay),
+	})
+}
+
+// Claim
+// Reserves the next available job for the visibility timeout. Jobs
+// are selected using SKIP LOCKED, and the reservation itself is
+// guarded by the version of the job, which makes claiming safe on
+// databases without row locks as well. Returns nil if no job
+// is available
+func (queue *Queue[P]) Claim() (*Reservation[P], error) {
+	var reservation *Reservation[P]
+
+	err := Repository.Transaction(func(config Repository.Config) error {
+		repository := Repository.Of[Job](config)
+
+		candidates := queue.claimable(repository).
+			OrderBy("available_at", "asc").
+			Take(claimBatchSize).
+			LockForUpdate().
+			SkipLocked().
+			Get()
+
+		for _, job := range candidates.All() {
+			// Jobs abandoned on their final attempt are dead-lettered
+			if job.Attempts >= job.MaxAttempts {
+				queue.bury(repository, &job, "Visibility timeout exceeded")
+
+				continue
+			}
+
+			if claimed := queue.reserve(repository, &job); !claimed {
+				continue
+			}
+
+			payload, err := Decode[P](&job)
+
+			if err != nil {
+				queue.bury(repository, &job, "Payload could not be decoded: "+err.Error())
+
+				continue
+			}
+
+			reservation = &Reservation[P]{Job: &job, Payload: payload}
+
+			return nil
+		}
+
+		return nil
+	}, queue.config()...)
+
+	return reservation, err
+}
+
+// Complete
+// Marks a claimed job as completed. Fails with ErrStaleModel if
+// the reservation expired and the job was claimed by another worker
+func (queue *Queue[P]) Complete(job *Job) error {
+	return queue.transition(job, StatusCompleted, map[string]any{
+		"reserved_at": nil,
+	})
+}
+
+// Fail
+// Releases a claimed job for a retry after the backoff delay or,
+// if no attempts remain, moves it to the dead-letter state
+func (queue *Queue[P]) Fail(job *Job, reason error) error {
+	if job.Attempts >= job.MaxAttempts {
+		return queue.transition(job, StatusDead, map[string]any{
+			"reserved_at": nil,
+			"last_error":  reason.Error(),
+		})
+	}
+
+	return queue.transition(job, StatusPending, map[string]any{
+		"reserved_at":  nil,
+		"last_error":   reason.Error(),
+		"available_at": now().Add(queue.options.Backoff(job.Attempts)),
+	})
+}
+
+// Work
+// Claims a job and handles it, completing it if the handler succeeds
+// and failing it if the handler returns an error or panics.
+// Returns false if no job was available
+func (queue *Queue[P]) Work(handler func(payload P) error) (bool, error) {
+	reservation, err := queue.Claim()
+
+	if err != nil || reservation == nil {
+		return false, err
+	}
+
+	if err = handle(handler, reservation.Payload); err != nil {
+		return true, queue.Fail(reservation.Job, err)
+	}
+
+	return true, queue.Complete(reservation.Job)
+}
+
+// Query
+// Starts a query builder for inspecting the jobs of the queue
+func (queue *Queue[P]) Query() *Repository.QueryBuilder[Job] {
+	return queue.repository().Query().Where("queue = ?", queue.name)
+}
+
+// Find
+// Gets a job of the queue by its id. Returns nil if not found
+func (queue *Queue[P]) Find(id uuid.UUID) *Job {
+	return queue.Query().Where("id = ?", id).First()
+}
+
+// Count
+// Gets the number of jobs of the queue in a specific state,
+// counted by the database without loading the jobs
+func (queue *Queue[P]) Count(status Status) int {
+	return int(Repository.Value[int64](queue.Query().Where("status = ?", status), "COUNT(*)"))
+}
+
+// Dead
+// Gets a collection of all dead-lettered jobs of the queue
+func (queue *Queue[P]) Dead() *Collection.Collection[Job] {
+	return queue.Query().Where("status = ?", StatusDead).OrderBy("updated_at", "asc").Get()
+}
+
+// Retry
+// Moves a dead-lettered job back onto the queue with its attempts reset
+func (queue *Queue[P]) Retry(id uuid.UUID) error {
+	job := queue.Find(id)
+
+	if job == nil || job.Status != StatusDead {
+		return errors.New(fmt.Sprintf("Queue[Retry]: No dead job with id %s", id))
+	}
+
+	return queue.transition(job, StatusPending, map[string]any{
+		"attempts":     0,
+		"last_error":   "",
+		"available_at": now(),
+	})
+}
+
+// Purge
+// Deletes all jobs of the queue in a specific state
+func (queue *Queue[P]) Purge(status Status) bool {
+	return queue.Query().Where("status = ?", status).Delete()
+}
+
+// claimable
+// Starts a query for jobs ready to be claimed. Reserved jobs
+// become available again once their visibility timeout expires
+func (queue *Queue[P]) claimable(repository *Repository.Repository[Job]) *Repository.QueryBuilder[Job] {
+	return repository.Query().
+		Where("queue = ?", queue.name).
+		Where("status IN ?", []Status{StatusPending, StatusReserved}).
+		Where("available_at <= ?", now())
+}
+
+// reserve
+// Reserves a job for the visibility timeout. Returns false if
+// the job was claimed by another worker in the meantime
+func (queue *Queue[P]) reserve(repository *Repository.Repository[Job], job *Job) bool {
+	reservedAt := now()
+
+	err := repository.Update(job.Id, map[string]any{
+		"status":       StatusReserved,
+		"attempts":     job.Attempts + 1,
+		"available_at": reservedAt.Add(queue.options.VisibilityTimeout),
+		"reserved_at":  reservedAt,
+		"version":      job.Version,
+	})
+
+	if err != nil {
+		return false
+	}
+
+	job.Status = StatusReserved
+	job.Attempts++
+	job.AvailableAt = reservedAt.Add(queue.options.VisibilityTimeout)
+	job.ReservedAt = &reservedAt
+	job.Version++
+
+	return true
+}
+
+// bury
+// Moves a job to the dead-letter state while claiming
+func (queue *Queue[P]) bury(repository *Repository.Repository[Job], job *Job, reason string) {
+	_ = repository.Update(job.Id, map[string]any{
+		"status":      StatusDead,
+		"reserved_at": nil,
+		"last_error":  reason,
+		"version":     job.Version,
+	})
+}
+
+// transition
+// Moves a job into a new state, guarded by the version of the job
+func (queue *Queue[P]) transition(job *Job, status Status, values map[string]any) error {
+	values["status"] = status
+	values["version"] = job.Version
+
+	if err := queue.repository().Update(job.Id, values); err != nil {
+		return err
+	}
+
+	job.Status = status
+	job.Version++
+
+	return nil
+}
+
+// repository
+// Gets a repository of jobs using the configuration of the queue
+func (queue *Queue[P]) repository() *Repository.Repository[Job] {
+	return Repository.Of[Job](queue.config()...)
+}
+
+// config
+// Gets the configuration of the queue, if any, as variadic arguments
+func (queue *Queue[P]) config() []Repository.Config {
+	if queue.options.Config == nil {
+		return nil
+	}
+
+	return []Repository.Config{*queue.options.Config}
+}
+
+// applyDefaultOptions
+// Assigns default values to options not set
+func (queue *Queue[P]) applyDefaultOptions() {
+	if queue.options.VisibilityTimeout == 0 {
+		queue.options.VisibilityTimeout = 5 * time.Minute
+	}
+
+	if queue.options.MaxAttempts == 0 {
+		queue.options.MaxAttempts = 3
+	}
+
+	if queue.options.Backoff == nil {
+		queue.options.Backoff = ExponentialBackoff(time.Second)
+	}
+}
+
+// handle
+// Runs a handler, turning panics into errors
+func handle[P any](handler func(payload P) error, payload P) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errors.New(fmt.Sprintf("Queue[Work]: Handler panicked: %v", recovered))
+		}
+	}()
+
+	return handler(payload)
+}
+
+// now
+// All queue timestamps are kept in UTC so they compare correctly
+func now() time.Time {
+	return time.Now().UTC()
+}
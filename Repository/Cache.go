@@ -0,0 +1,301 @@
+package Repository
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Cache interface {
+	// Get
+	// Gets a cached value. Returns false if missing or expired
+	Get(key string) ([]byte, bool)
+
+	// Set
+	// Caches a value for a duration, tagged for invalidation
+	Set(key string, value []byte, ttl time.Duration, tags ...string)
+
+	// Invalidate
+	// Removes all cached values carrying any of the tags
+	Invalidate(tags ...string)
+}
+
+// Remember
+// Caches the results of the query for a duration. Results are
+// invalidated when the model is written through the repository.
+// Does nothing if no cache is configured
+func (builder *QueryBuilder[T]) Remember(ttl time.Duration) *QueryBuilder[T] {
+	builder.remember = ttl
+
+	return builder
+}
+
+// shouldRemember
+// Tells if the results of the query should be cached
+func (builder *QueryBuilder[T]) shouldRemember() bool {
+	return builder.cache != nil && builder.remember > 0 && !inTransaction(builder.query)
+}
+
+// cacheTag
+// Gets the tag all cached results of the model are stored under
+func (builder *QueryBuilder[T]) cacheTag() string {
+	return parseSchema(builder.query, builder.model).Table
+}
+
+// invalidateCache
+// Invalidates all cached results of the model
+func (builder *QueryBuilder[T]) invalidateCache() {
+	if builder.cache == nil {
+		return
+	}
+
+	invalidate(builder.cache, builder.invalidations, builder.cacheTag())
+}
+
+// shouldRemember
+// Tells if the results of the query should be cached
+func (repository *Repository[T]) shouldRemember(query *gorm.DB) bool {
+	return repository.cache != nil && repository.cacheTTL > 0 && !inTransaction(query)
+}
+
+// invalidateCache
+// Invalidates all cached results of the model
+func (repository *Repository[T]) invalidateCache() {
+	if repository.cache == nil {
+		return
+	}
+
+	invalidate(repository.cache, repository.invalidations, repository.schema().Table)
+}
+
+// invalidations
+// Keeps track of tags written inside a transaction. These are
+// invalidated once more when the transaction is committed, as
+// results may have been cached before the writes became visible
+type invalidations struct {
+	mutex sync.Mutex
+	tags  []string
+}
+
+// add
+// Records tags written inside the transaction
+func (pending *invalidations) add(tags ...string) {
+	pending.mutex.Lock()
+	defer pending.mutex.Unlock()
+
+	pending.tags = append(pending.tags, tags...)
+}
+
+// flush
+// Invalidates all tags written inside the transaction
+func (pending *invalidations) flush(cache Cache) {
+	pending.mutex.Lock()
+	defer pending.mutex.Unlock()
+
+	if cache != nil && len(pending.tags) > 0 {
+		cache.Invalidate(pending.tags...)
+	}
+
+	pending.tags = nil
+}
+
+// invalidate
+// Invalidates tags in the cache and records
+// them if written inside a transaction
+func invalidate(cache Cache, pending *invalidations, tags ...string) {
+	cache.Invalidate(tags...)
+
+	if pending != nil {
+		pending.add(tags...)
+	}
+}
+
+// inTransaction
+// Tells if a query is part of a transaction
+func inTransaction(query *gorm.DB) bool {
+	_, ok := query.Statement.ConnPool.(gorm.TxCommitter)
+
+	return ok
+}
+
+// remember
+// Gets a cached result or loads and caches it
+func remember[V any](cache Cache, ttl time.Duration, key string, tag string, load func() V) V {
+	if cached, exists := cache.Get(key); exists {
+		var value V
+
+		if err := json.Unmarshal(cached, &value); err == nil {
+			return value
+		}
+	}
+
+	value := load()
+
+	if encoded, err := json.Marshal(value); err == nil {
+		cache.Set(key, encoded, ttl, tag)
+	}
+
+	return value
+}
+
+// cacheKey
+// Derives a cache key from the SQL and bindings a query would
+// execute, without executing it. Relationships to preload are
+// not part of the SQL, so they are added to the key separately
+func cacheKey(query *gorm.DB, finisher func(query *gorm.DB) *gorm.DB) string {
+	preloads := make([]string, 0, len(query.Statement.Preloads))
+
+	for preload := range query.Statement.Preloads {
+		preloads = append(preloads, preload)
+	}
+
+	sort.Strings(preloads)
+
+	statement := dryRun(query, finisher)
+	hash := sha256.New()
+
+	hash.Write([]byte(statement.SQL.String()))
+
+	for _, binding := range statement.Vars {
+		hash.Write([]byte(fmt.Sprintf("|%T:%v", binding, binding)))
+	}
+
+	for _, preload := range preloads {
+		hash.Write([]byte("|preload:" + preload))
+	}
+
+	return "repository:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+type MemoryCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	tags     map[string]map[string]struct{}
+}
+
+type memoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+// NewMemoryCache
+// Named constructor for creating an in-memory cache holding at
+// most capacity entries, evicting the least recently used
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+		tags:     map[string]map[string]struct{}{},
+	}
+}
+
+// Get
+// Gets a cached value. Returns false if missing or expired
+func (cache *MemoryCache) Get(key string) ([]byte, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, exists := cache.entries[key]
+
+	if !exists {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryCacheEntry)
+
+	if time.Now().After(entry.expiresAt) {
+		cache.remove(element)
+
+		return nil, false
+	}
+
+	cache.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+// Set
+// Caches a value for a duration, tagged for invalidation
+func (cache *MemoryCache) Set(key string, value []byte, ttl time.Duration, tags ...string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, exists := cache.entries[key]; exists {
+		cache.remove(element)
+	}
+
+	entry := &memoryCacheEntry{
+		key:       key,
+		value:     value,
+		expiresAt: time.Now().Add(ttl),
+		tags:      tags,
+	}
+
+	cache.entries[key] = cache.order.PushFront(entry)
+
+	for _, tag := range tags {
+		if cache.tags[tag] == nil {
+			cache.tags[tag] = map[string]struct{}{}
+		}
+
+		cache.tags[tag][key] = struct{}{}
+	}
+
+	for cache.capacity > 0 && cache.order.Len() > cache.capacity {
+		cache.remove(cache.order.Back())
+	}
+}
+
+// Invalidate
+// Removes all cached values carrying any of the tags
+func (cache *MemoryCache) Invalidate(tags ...string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for _, tag := range tags {
+		for key := range cache.tags[tag] {
+			if element, exists := cache.entries[key]; exists {
+				cache.remove(element)
+			}
+		}
+
+		delete(cache.tags, tag)
+	}
+}
+
+// Len
+// Gets the number of entries in the cache
+func (cache *MemoryCache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.order.Len()
+}
+
+// remove
+// Removes an entry from the cache
+func (cache *MemoryCache) remove(element *list.Element) {
+	entry := element.Value.(*memoryCacheEntry)
+
+	cache.order.Remove(element)
+	delete(cache.entries, entry.key)
+
+	for _, tag := range entry.tags {
+		delete(cache.tags[tag], entry.key)
+
+		if len(cache.tags[tag]) == 0 {
+			delete(cache.tags, tag)
+		}
+	}
+}
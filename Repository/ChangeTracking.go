@@ -0,0 +1,249 @@
+package Repository
+
+import (
+	"context"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"sync"
+)
+
+// snapshots
+// Holds the values of models as they were loaded, keyed by primary key
+type snapshots struct {
+	mutex  sync.Mutex
+	values map[any]map[string]any
+}
+
+// Track
+// Makes the repository take snapshots of the models it loads, so
+// changes made to them can be told apart. Snapshots are kept until
+// forgotten, or for as long as the repository is
+func (repository *Repository[T]) Track() *Repository[T] {
+	if repository.snapshots == nil {
+		repository.snapshots = &snapshots{}
+	}
+
+	return repository
+}
+
+// Forget
+// Drops the snapshots of models, or of all models if none are passed,
+// after which they are considered changed entirely
+func (repository *Repository[T]) Forget(models ...*T) *Repository[T] {
+	if repository.snapshots == nil {
+		return repository
+	}
+
+	if len(models) == 0 {
+		repository.snapshots.clear()
+
+		return repository
+	}
+
+	modelSchema := repository.schema()
+
+	for _, model := range models {
+		repository.snapshots.forget(identityValue(modelSchema, model))
+	}
+
+	return repository
+}
+
+// IsDirty
+// Tells if a model was changed since it was loaded through the
+// repository. Pass field or column names to only check those
+func (repository *Repository[T]) IsDirty(model *T, fields ...string) bool {
+	changes := repository.GetChanges(model)
+
+	if len(fields) == 0 {
+		return len(changes) > 0
+	}
+
+	modelSchema := repository.schema()
+
+	for _, name := range fields {
+		if field := modelSchema.LookUpField(name); field != nil {
+			if _, changed := changes[field.DBName]; changed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// GetChanges
+// Gets the columns of a model changed since it was loaded through
+// the repository, holding the current values. Models not loaded
+// through a tracking repository are considered changed entirely
+func (repository *Repository[T]) GetChanges(model *T) map[string]any {
+	modelSchema := repository.schema()
+	snapshot := repository.snapshots.get(identityValue(modelSchema, model))
+
+	return diffSnapshot(modelSchema, reflect.ValueOf(model).Elem(), snapshot)
+}
+
+// SaveChanges
+// Updates only the columns of a model changed since it was loaded
+func (repository *Repository[T]) SaveChanges(model *T) (err error) {
+	repository, finish := repository.observe("SaveChanges")
+	defer finish(&err)
+
+	changes := repository.GetChanges(model)
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := repository.updateChanges(model, changes); err != nil {
+		return err
+	}
+
+	repository.trackSnapshots(model)
+
+	return nil
+}
+
+// updateChanges
+// Updates columns of a model. Versioned models are updated based on
+// the version they were loaded with and carry the incremented
+// version afterwards
+func (repository *Repository[T]) updateChanges(model *T, changes map[string]any) error {
+	modelSchema := repository.schema()
+	value := reflect.ValueOf(model).Elem()
+	versionField := repository.versionField()
+
+	if versionField != nil {
+		version, _ := versionField.ValueOf(context.Background(), value)
+		changes[versionField.DBName] = version
+	}
+
+	if err := repository.Update(primaryKeyOf(modelSchema, model), changes); err != nil {
+		return err
+	}
+
+	if versionField != nil {
+		version, _ := toInt64(reflect.ValueOf(changes[versionField.DBName]))
+		_ = versionField.Set(context.Background(), value, version+1)
+	}
+
+	return nil
+}
+
+// trackSnapshots
+// Takes snapshots of models loaded through the repository
+func (repository *Repository[T]) trackSnapshots(models ...*T) {
+	if repository.snapshots == nil {
+		return
+	}
+
+	modelSchema := repository.schema()
+
+	for _, model := range models {
+		repository.snapshots.track(modelSchema, model)
+	}
+}
+
+// trackEntries
+// Takes snapshots of entries loaded through the repository
+func (repository *Repository[T]) trackEntries(entries []T) {
+	for index := range entries {
+		repository.trackSnapshots(&entries[index])
+	}
+}
+
+// trackEntries
+// Takes snapshots of entries loaded through the query builder
+func (builder *QueryBuilder[T]) trackEntries(entries []T) {
+	for index := range entries {
+		builder.trackSnapshots(&entries[index])
+	}
+}
+
+// trackSnapshots
+// Takes snapshots of models loaded through the query builder
+func (builder *QueryBuilder[T]) trackSnapshots(models ...*T) {
+	if builder.snapshots == nil {
+		return
+	}
+
+	modelSchema := parseSchema(builder.query, builder.model)
+
+	for _, model := range models {
+		builder.snapshots.track(modelSchema, model)
+	}
+}
+
+// track
+// Takes a snapshot of a model
+func (store *snapshots) track(modelSchema *schema.Schema, model any) {
+	if reflect.ValueOf(model).IsNil() {
+		return
+	}
+
+	id := identityValue(modelSchema, model)
+
+	if id == nil {
+		return
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if store.values == nil {
+		store.values = map[any]map[string]any{}
+	}
+
+	store.values[id] = takeSnapshot(modelSchema, reflect.ValueOf(model).Elem())
+}
+
+// get
+// Gets the snapshot of a model, if any
+func (store *snapshots) get(id any) map[string]any {
+	if store == nil || id == nil {
+		return nil
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return store.values[id]
+}
+
+// forget
+// Drops the snapshot of a model, if any
+func (store *snapshots) forget(id any) {
+	if id == nil {
+		return
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.values, id)
+}
+
+// clear
+// Drops all snapshots
+func (store *snapshots) clear() {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.values = nil
+}
+
+// identityValue
+// Gets the primary key of a model. Returns nil if not set
+func identityValue(modelSchema *schema.Schema, model any) any {
+	if modelSchema.PrioritizedPrimaryField == nil {
+		return nil
+	}
+
+	id, isZero := modelSchema.PrioritizedPrimaryField.ValueOf(context.Background(), reflect.ValueOf(model).Elem())
+
+	if isZero {
+		return nil
+	}
+
+	return id
+}
@@ -0,0 +1,149 @@
+package Repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-support/Support"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"strconv"
+	"strings"
+)
+
+// parentTag
+// The struct tag value marking a field as the parent key
+const parentTag = "parent"
+
+type WithParent interface {
+	ParentColumn() string
+}
+
+// WithCTE
+// Adds a common table expression named name to the query, which
+// can be referenced like a table in joins, wheres and subqueries
+func (builder *QueryBuilder[T]) WithCTE(name string, query Subquery) *QueryBuilder[T] {
+	return builder.withCTE(false, name, "?", query.subquery())
+}
+
+// WithRecursiveCTE
+// Adds a recursive common table expression named name to the query.
+// The anchor query selects the starting rows, the recursive query
+// references name to select the rows following those. Results of
+// both are combined using UNION ALL
+func (builder *QueryBuilder[T]) WithRecursiveCTE(name string, anchor Subquery, recursive Subquery) *QueryBuilder[T] {
+	return builder.withCTE(true, name, "? UNION ALL ?", anchor.subquery(), recursive.subquery())
+}
+
+// Descendants
+// Limits the query to the children of the entry with the given id,
+// their children and so on. Requires the model to declare a parent key
+func (builder *QueryBuilder[T]) Descendants(id uuid.UUID) *QueryBuilder[T] {
+	table, primaryKey, parentKey := builder.hierarchy("Descendants")
+
+	name := builder.cteName("descendants")
+
+	builder.withCTE(true, name, "SELECT ? AS id FROM ? WHERE ? = ? UNION SELECT ? FROM ? JOIN ? ON ? = ?",
+		clause.Column{Name: primaryKey}, clause.Table{Name: table}, clause.Column{Name: parentKey}, id,
+		clause.Column{Table: table, Name: primaryKey}, clause.Table{Name: table}, clause.Table{Name: name}, clause.Column{Table: table, Name: parentKey}, clause.Column{Table: name, Name: "id"},
+	)
+
+	builder.query = builder.query.Where("? IN (SELECT id FROM ?)", clause.Column{Table: builder.table(), Name: primaryKey}, clause.Table{Name: name})
+
+	return builder
+}
+
+// Ancestors
+// Limits the query to the parent of the entry with the given id,
+// its parent and so on. Requires the model to declare a parent key
+func (builder *QueryBuilder[T]) Ancestors(id uuid.UUID) *QueryBuilder[T] {
+	table, primaryKey, parentKey := builder.hierarchy("Ancestors")
+
+	name := builder.cteName("ancestors")
+
+	builder.withCTE(true, name, "SELECT ? AS id FROM ? WHERE ? = ? UNION SELECT ? FROM ? JOIN ? ON ? = ?",
+		clause.Column{Name: parentKey}, clause.Table{Name: table}, clause.Column{Name: primaryKey}, id,
+		clause.Column{Table: table, Name: parentKey}, clause.Table{Name: table}, clause.Table{Name: name}, clause.Column{Table: table, Name: primaryKey}, clause.Column{Table: name, Name: "id"},
+	)
+
+	builder.query = builder.query.Where("? IN (SELECT id FROM ?)", clause.Column{Table: builder.table(), Name: primaryKey}, clause.Table{Name: name})
+
+	return builder
+}
+
+// withCTE
+// Adds a common table expression to the query. The expressions are
+// written in front of the SELECT clause, so they are kept when the
+// query is counted, paginated or used as a subquery
+func (builder *QueryBuilder[T]) withCTE(recursive bool, name string, sql string, vars ...any) *QueryBuilder[T] {
+	builder.ctes = append(builder.ctes, clause.Expr{
+		SQL:  "? AS (" + sql + ")",
+		Vars: append([]any{clause.Table{Name: name}}, vars...),
+	})
+
+	builder.recursive = builder.recursive || recursive
+
+	keyword := "WITH "
+
+	if builder.recursive {
+		keyword = "WITH RECURSIVE "
+	}
+
+	expressions := make([]string, 0, len(builder.ctes))
+	var expressionVars []any
+
+	for _, expression := range builder.ctes {
+		expressions = append(expressions, expression.SQL)
+		expressionVars = append(expressionVars, expression.Vars...)
+	}
+
+	builder.query = builder.query.Clauses()
+
+	selectClause := builder.query.Statement.Clauses["SELECT"]
+	selectClause.Name = "SELECT"
+	selectClause.BeforeExpression = clause.Expr{SQL: keyword + strings.Join(expressions, ", "), Vars: expressionVars}
+	builder.query.Statement.Clauses["SELECT"] = selectClause
+
+	return builder
+}
+
+// cteName
+// Names a common table expression added by the query builder itself,
+// so it does not clash with expressions added before it
+func (builder *QueryBuilder[T]) cteName(name string) string {
+	return name + "_" + strconv.Itoa(len(builder.ctes))
+}
+
+// hierarchy
+// Resolves the table, primary key and parent key used
+// to walk the hierarchy of self-referencing models
+func (builder *QueryBuilder[T]) hierarchy(method string) (string, string, string) {
+	modelSchema := parseSchema(builder.query, builder.model)
+	parent := parentField(modelSchema, builder.model)
+
+	if parent == nil {
+		panic("QueryBuilder[" + method + "]: Model does not declare a parent key")
+	}
+
+	if modelSchema.PrioritizedPrimaryField == nil {
+		panic("QueryBuilder[" + method + "]: Model does not have a primary key")
+	}
+
+	return builder.table(), modelSchema.PrioritizedPrimaryField.DBName, parent.DBName
+}
+
+// parentField
+// Finds the schema field referencing the parent entry, either
+// declared by the WithParent interface or tagged with
+// `repository:"parent"`. Returns nil for models without one
+func parentField(modelSchema *schema.Schema, model any) *schema.Field {
+	if Support.Implements[WithParent](model) {
+		return modelSchema.LookUpField(Support.Cast[WithParent](model).ParentColumn())
+	}
+
+	for _, field := range modelSchema.Fields {
+		if field.Tag.Get("repository") == parentTag {
+			return field
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nbj/go-support/Support"
+	"sync"
+)
+
+// DefaultConnection
+// The name of the connection used when nothing else is specified
+const DefaultConnection = "default"
+
+// ErrMissingConfiguration
+// Returned when a repository cannot be resolved to a configuration
+var ErrMissingConfiguration = errors.New("Repository: No configuration available")
+
+var connections = map[string]*Config{}
+var connectionsMutex sync.RWMutex
+
+type WithConnection interface {
+	Connection() string
+}
+
+// AddConnection
+// Registers a named configuration repositories can be instantiated with
+func AddConnection(name string, config Config) {
+	connectionsMutex.Lock()
+	defer connectionsMutex.Unlock()
+
+	connections[name] = &config
+}
+
+// RemoveConnection
+// Removes a named configuration from the registry
+func RemoveConnection(name string) {
+	connectionsMutex.Lock()
+	defer connectionsMutex.Unlock()
+
+	delete(connections, name)
+}
+
+// Connection
+// Gets a named configuration from the registry
+func Connection(name string) (*Config, error) {
+	connectionsMutex.RLock()
+	defer connectionsMutex.RUnlock()
+
+	config, exists := connections[name]
+
+	if !exists || config == nil {
+		return nil, fmt.Errorf("%w for connection \"%s\"", ErrMissingConfiguration, name)
+	}
+
+	return config, nil
+}
+
+// resolveConfiguration
+// Finds the configuration for a model. A configuration passed
+// explicitly takes precedence, followed by the connection declared
+// by the model, falling back to the default connection
+func resolveConfiguration(model any, config []Config) (*Config, error) {
+	if len(config) > 0 {
+		return &config[0], nil
+	}
+
+	if Support.Implements[WithConnection](model) {
+		return Connection(Support.Cast[WithConnection](model).Connection())
+	}
+
+	return Connection(DefaultConnection)
+}
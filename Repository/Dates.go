@@ -0,0 +1,212 @@
+package Repository
+
+import (
+	"fmt"
+	"gorm.io/gorm/clause"
+	"regexp"
+	"time"
+)
+
+// timeOfDay
+// Matches times of day passed to WhereTime
+var timeOfDay = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9])?$`)
+
+// WhereDate
+// Filters rows by the calendar date of a column, in the timezone of the
+// configuration. Only the year, month and day of date are used
+func (builder *QueryBuilder[T]) WhereDate(column string, operator string, date time.Time) *QueryBuilder[T] {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, builder.location())
+
+	return builder.wherePeriod("WhereDate", column, operator, start, start.AddDate(0, 0, 1))
+}
+
+// WhereYear
+// Filters rows by the year of a column, in the timezone of the configuration
+func (builder *QueryBuilder[T]) WhereYear(column string, operator string, year int) *QueryBuilder[T] {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, builder.location())
+
+	return builder.wherePeriod("WhereYear", column, operator, start, start.AddDate(1, 0, 0))
+}
+
+// WhereMonth
+// Filters rows by the month of a column regardless of the year,
+// in the timezone of the configuration
+func (builder *QueryBuilder[T]) WhereMonth(column string, operator string, month time.Month) *QueryBuilder[T] {
+	if !comparisonOperators[operator] {
+		panic("QueryBuilder[WhereMonth]: Invalid operator " + operator)
+	}
+
+	if month < time.January || month > time.December {
+		panic(fmt.Sprintf("QueryBuilder[WhereMonth]: Invalid month %d", month))
+	}
+
+	expression := builder.datePart("WhereMonth", column, "month")
+	builder.query = builder.query.Where(clause.Expr{
+		SQL:  expression.SQL + " " + operator + " ?",
+		Vars: append(expression.Vars, int(month)),
+	})
+
+	return builder
+}
+
+// WhereTime
+// Filters rows by the time of day of a column, formatted as HH:MM or
+// HH:MM:SS, regardless of the date, in the timezone of the configuration
+func (builder *QueryBuilder[T]) WhereTime(column string, operator string, value string) *QueryBuilder[T] {
+	if !comparisonOperators[operator] {
+		panic("QueryBuilder[WhereTime]: Invalid operator " + operator)
+	}
+
+	if !timeOfDay.MatchString(value) {
+		panic("QueryBuilder[WhereTime]: Invalid time " + value)
+	}
+
+	if len(value) == len("15:04") {
+		value += ":00"
+	}
+
+	expression := builder.datePart("WhereTime", column, "time")
+	builder.query = builder.query.Where(clause.Expr{
+		SQL:  expression.SQL + " " + operator + " ?",
+		Vars: append(expression.Vars, value),
+	})
+
+	return builder
+}
+
+// WhereBefore
+// Filters rows by a column being before a moment
+func (builder *QueryBuilder[T]) WhereBefore(column string, moment time.Time) *QueryBuilder[T] {
+	builder.query = builder.query.Where("? < ?", builder.resolveColumn("WhereBefore", column, false), builder.storedTime(moment))
+
+	return builder
+}
+
+// WhereAfter
+// Filters rows by a column being after a moment
+func (builder *QueryBuilder[T]) WhereAfter(column string, moment time.Time) *QueryBuilder[T] {
+	builder.query = builder.query.Where("? > ?", builder.resolveColumn("WhereAfter", column, false), builder.storedTime(moment))
+
+	return builder
+}
+
+// WhereWithinLast
+// Filters rows by a column being within the duration up until now,
+// by created_at unless another column is given
+func (builder *QueryBuilder[T]) WhereWithinLast(duration time.Duration, column ...string) *QueryBuilder[T] {
+	now := builder.query.NowFunc()
+
+	builder.query = builder.query.Where("? >= ?",
+		builder.resolveColumn("WhereWithinLast", timestampColumn(column), false),
+		builder.storedTime(now.Add(-duration)),
+	)
+
+	return builder
+}
+
+// wherePeriod
+// Compares a column to a period starting at start and ending before end.
+// Comparing to ranges rather than parts of dates keeps indexes usable
+func (builder *QueryBuilder[T]) wherePeriod(method string, column string, operator string, start time.Time, end time.Time) *QueryBuilder[T] {
+	resolved := builder.resolveColumn(method, column, false)
+	start, end = builder.storedTime(start), builder.storedTime(end)
+
+	switch operator {
+	case "=":
+		builder.query = builder.query.Where("? >= ? AND ? < ?", resolved, start, resolved, end)
+	case "!=", "<>":
+		builder.query = builder.query.Where("(? < ? OR ? >= ?)", resolved, start, resolved, end)
+	case "<":
+		builder.query = builder.query.Where("? < ?", resolved, start)
+	case "<=":
+		builder.query = builder.query.Where("? < ?", resolved, end)
+	case ">":
+		builder.query = builder.query.Where("? >= ?", resolved, end)
+	case ">=":
+		builder.query = builder.query.Where("? >= ?", resolved, start)
+	default:
+		panic("QueryBuilder[" + method + "]: Invalid operator " + operator)
+	}
+
+	return builder
+}
+
+// datePart
+// Compiles the extraction of the month or time of day of a column,
+// converted to the timezone of the configuration. Postgres and MySQL
+// convert to named timezones, which on MySQL requires its timezone
+// tables to be loaded. SQLite only knows offsets, so it only supports
+// timezones with a fixed offset, and panics on timezones observing
+// daylight saving time
+func (builder *QueryBuilder[T]) datePart(method string, column string, part string) clause.Expr {
+	resolved := builder.resolveColumn(method, column, false)
+	location := builder.location()
+	offset, fixed := builder.fixedOffset(location)
+
+	switch builder.query.Dialector.Name() {
+	case "postgres":
+		if part == "month" {
+			return clause.Expr{SQL: "EXTRACT(MONTH FROM ? AT TIME ZONE ?)", Vars: []any{resolved, location.String()}}
+		}
+
+		return clause.Expr{SQL: "to_char(? AT TIME ZONE ?, 'HH24:MI:SS')", Vars: []any{resolved, location.String()}}
+	case "mysql":
+		zone := location.String()
+
+		if fixed {
+			sign := "+"
+
+			if offset < 0 {
+				sign, offset = "-", -offset
+			}
+
+			zone = fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset%3600/60)
+		}
+
+		if part == "month" {
+			return clause.Expr{SQL: "MONTH(CONVERT_TZ(?, '+00:00', ?))", Vars: []any{resolved, zone}}
+		}
+
+		return clause.Expr{SQL: "DATE_FORMAT(CONVERT_TZ(?, '+00:00', ?), '%H:%i:%S')", Vars: []any{resolved, zone}}
+	default:
+		if !fixed {
+			panic("QueryBuilder[" + method + "]: Timezone " + location.String() + " does not have a fixed offset, which " + builder.query.Dialector.Name() + " requires")
+		}
+
+		modifier := fmt.Sprintf("%+d minutes", offset/60)
+
+		if part == "month" {
+			return clause.Expr{SQL: "CAST(strftime('%m', ?, ?) AS INTEGER)", Vars: []any{resolved, modifier}}
+		}
+
+		return clause.Expr{SQL: "strftime('%H:%M:%S', ?, ?)", Vars: []any{resolved, modifier}}
+	}
+}
+
+// fixedOffset
+// Gets the offset of a timezone from UTC, and tells if it is the same
+// all year round, which it is not for timezones observing daylight saving time
+func (builder *QueryBuilder[T]) fixedOffset(location *time.Location) (int, bool) {
+	year := builder.query.NowFunc().Year()
+	_, winter := time.Date(year, time.January, 1, 0, 0, 0, 0, location).Zone()
+	_, summer := time.Date(year, time.July, 1, 0, 0, 0, 0, location).Zone()
+
+	return winter, winter == summer
+}
+
+// location
+// Gets the timezone dates are interpreted in
+func (builder *QueryBuilder[T]) location() *time.Location {
+	if builder.timezone != nil {
+		return builder.timezone
+	}
+
+	return time.UTC
+}
+
+// storedTime
+// Converts a moment to the location timestamps are written in, so
+// moments compare correctly to timestamps stored as text on SQLite
+func (builder *QueryBuilder[T]) storedTime(moment time.Time) time.Time {
+	return moment.In(builder.query.NowFunc().Location())
+}
@@ -0,0 +1,166 @@
+package Repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"strings"
+)
+
+type Plan struct {
+	// The statement explained, with bindings interpolated
+	SQL string
+
+	// The rows returned by the database explaining the statement
+	Rows []map[string]any
+}
+
+// String
+// Formats the plan one row per line. The detail column of SQLite
+// and the QUERY PLAN column of Postgres are written as they are,
+// other rows as their columns in alphabetical order
+func (plan *Plan) String() string {
+	lines := make([]string, 0, len(plan.Rows))
+
+	for _, row := range plan.Rows {
+		if detail, exists := row["detail"]; exists {
+			lines = append(lines, fmt.Sprint(detail))
+
+			continue
+		}
+
+		if detail, exists := row["QUERY PLAN"]; exists {
+			lines = append(lines, fmt.Sprint(detail))
+
+			continue
+		}
+
+		columns := make([]string, 0, len(row))
+
+		for column := range row {
+			columns = append(columns, column)
+		}
+
+		sort.Strings(columns)
+
+		parts := make([]string, 0, len(columns))
+
+		for _, column := range columns {
+			parts = append(parts, fmt.Sprintf("%s=%v", column, row[column]))
+		}
+
+		lines = append(lines, strings.Join(parts, " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ToSQL
+// Gets the statement and bindings the query would execute, without executing it
+func (builder *QueryBuilder[T]) ToSQL() (string, []any) {
+	statement := dryRun(builder.compiled(), func(query *gorm.DB) *gorm.DB {
+		return query.Find(&[]T{})
+	})
+
+	return statement.SQL.String(), statement.Vars
+}
+
+// ToRawSQL
+// Gets the statement the query would execute with the bindings
+// interpolated. Meant for logs, never for executing
+func (builder *QueryBuilder[T]) ToRawSQL() string {
+	sql, vars := builder.ToSQL()
+
+	return builder.query.Dialector.Explain(sql, vars...)
+}
+
+// Dump
+// Prints the statement the query would execute and continues the chain
+func (builder *QueryBuilder[T]) Dump() *QueryBuilder[T] {
+	fmt.Println(builder.ToRawSQL())
+
+	return builder
+}
+
+// Explain
+// Asks the database how it would execute the query, using
+// EXPLAIN QUERY PLAN on SQLite and EXPLAIN elsewhere
+func (builder *QueryBuilder[T]) Explain() *Plan {
+	sql, vars := builder.ToSQL()
+	query := builder.compiled()
+	prefix := "EXPLAIN "
+
+	if query.Dialector.Name() == "sqlite" {
+		prefix = "EXPLAIN QUERY PLAN "
+	}
+
+	queryContext := query.Statement.Context
+
+	if queryContext == nil {
+		queryContext = context.Background()
+	}
+
+	rows, err := query.Statement.ConnPool.QueryContext(queryContext, prefix+sql, vars...)
+
+	if err != nil {
+		panic("QueryBuilder[Explain]: " + err.Error())
+	}
+
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+
+	if err != nil {
+		panic("QueryBuilder[Explain]: " + err.Error())
+	}
+
+	plan := &Plan{SQL: query.Dialector.Explain(sql, vars...)}
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+
+		for index := range values {
+			pointers[index] = &values[index]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			panic("QueryBuilder[Explain]: " + err.Error())
+		}
+
+		row := make(map[string]any, len(columns))
+
+		for index, column := range columns {
+			if bytes, isBytes := values[index].([]byte); isBytes {
+				row[column] = string(bytes)
+			} else {
+				row[column] = values[index]
+			}
+		}
+
+		plan.Rows = append(plan.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		panic("QueryBuilder[Explain]: " + err.Error())
+	}
+
+	return plan
+}
+
+// dryRun
+// Compiles the statement and bindings a query would execute, without
+// executing it. Relationships to preload are left out of the compilation
+func dryRun(query *gorm.DB, finisher func(query *gorm.DB) *gorm.DB) *gorm.Statement {
+	queryContext := query.Statement.Context
+
+	if queryContext == nil {
+		queryContext = context.Background()
+	}
+
+	session := query.Session(&gorm.Session{DryRun: true, Context: queryContext})
+	session.Statement.Preloads = map[string][]interface{}{}
+
+	return finisher(session).Statement
+}
@@ -0,0 +1,257 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+// ErrFullTextNotSupported
+// Returned by CreateFullTextIndex for dialects without full-text search
+var ErrFullTextNotSupported = errors.New("Repository[CreateFullTextIndex]: Full-text search is not supported")
+
+// fullTextConfiguration
+// The text search configuration used on Postgres
+const fullTextConfiguration = "simple"
+
+// relevanceColumn
+// The alias of the relevance selected by OrderByRelevance
+const relevanceColumn = "relevance"
+
+// WhereFullText
+// Filters rows by searching columns for all words of a term, using
+// FTS5 on SQLite, tsvector on Postgres and MATCH ... AGAINST on MySQL.
+// The columns must be indexed with CreateFullTextIndex. Empty terms
+// do not filter the rows
+func (builder *QueryBuilder[T]) WhereFullText(columns []string, term string) *QueryBuilder[T] {
+	words := strings.Fields(term)
+
+	if len(words) == 0 {
+		return builder
+	}
+
+	if len(columns) == 0 {
+		panic("QueryBuilder[WhereFullText]: No columns to search")
+	}
+
+	resolved := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		resolved = append(resolved, builder.resolveColumn("WhereFullText", column, false).Name)
+	}
+
+	table := builder.table()
+	statement := builder.query.Statement
+
+	switch builder.query.Dialector.Name() {
+	case "sqlite":
+		indexed, err := builder.tenancy.qualifiedTable(parseSchema(builder.query, builder.model))
+
+		if err != nil {
+			panic("QueryBuilder[WhereFullText]: " + err.Error())
+		}
+
+		// The FTS5 table is matched by its name without a schema,
+		// as SQLite would take a qualified name for a column
+		index := statement.Quote(clause.Table{Name: fullTextTable(indexed)})
+		matched := statement.Quote(clause.Table{Name: fullTextTable(indexed[strings.LastIndex(indexed, ".")+1:])})
+		key := fullTextKey(builder.query, builder.model)
+		phrases := make([]string, 0, len(words))
+
+		for _, word := range words {
+			phrases = append(phrases, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+		}
+
+		match := "{" + strings.Join(resolved, " ") + "} : (" + strings.Join(phrases, " ") + ")"
+		indexedKey := matched + "." + statement.Quote(key)
+
+		builder.query = builder.query.Where("? IN (SELECT "+indexedKey+" FROM "+index+" WHERE "+matched+" MATCH ?)", clause.Column{Table: table, Name: key}, match)
+		builder.relevance = &clause.Expr{
+			SQL:  "(SELECT -rank FROM " + index + " WHERE " + matched + " MATCH ? AND " + indexedKey + " = ?)",
+			Vars: []any{match, clause.Column{Table: table, Name: key}},
+		}
+	case "postgres":
+		document := fullTextDocument(statement, table, resolved)
+
+		builder.query = builder.query.Where(document+" @@ plainto_tsquery('"+fullTextConfiguration+"', ?)", term)
+		builder.relevance = &clause.Expr{
+			SQL:  "ts_rank(" + document + ", plainto_tsquery('" + fullTextConfiguration + "', ?))",
+			Vars: []any{term},
+		}
+	case "mysql":
+		quoted := make([]string, 0, len(resolved))
+
+		for _, column := range resolved {
+			quoted = append(quoted, statement.Quote(clause.Column{Table: table, Name: column}))
+		}
+
+		match := "MATCH (" + strings.Join(quoted, ", ") + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+
+		builder.query = builder.query.Where(match, term)
+		builder.relevance = &clause.Expr{SQL: match, Vars: []any{term}}
+	default:
+		panic("QueryBuilder[WhereFullText]: Full-text search is not supported by " + builder.query.Dialector.Name())
+	}
+
+	return builder
+}
+
+// OrderByRelevance
+// Orders the results by how well they match the term passed to WhereFullText,
+// best matches first. The relevance is selected as the relevance column
+func (builder *QueryBuilder[T]) OrderByRelevance() *QueryBuilder[T] {
+	if builder.relevance == nil {
+		panic("QueryBuilder[OrderByRelevance]: No full-text search to order by")
+	}
+
+	builder.addSelect(clause.Expr{
+		SQL:  builder.relevance.SQL + " AS ?",
+		Vars: append(append([]any{}, builder.relevance.Vars...), clause.Column{Name: relevanceColumn}),
+	})
+
+	return builder.addOrder(clause.OrderByColumn{Column: clause.Column{Name: relevanceColumn}, Desc: true})
+}
+
+// CreateFullTextIndex
+// Creates the index needed to search columns with WhereFullText. On SQLite
+// an FTS5 table is created alongside the table of the model, kept up to
+// date by triggers and filled with the existing rows. The FTS5 table holds
+// a copy of the columns keyed by the primary key of the model, as rowids
+// of tables with other primary keys may change when the database is vacuumed.
+// Tenants kept apart by table get an index on the table of the tenant
+func (repository *Repository[T]) CreateFullTextIndex(columns ...string) error {
+	query := repository.connection
+	modelSchema := repository.schema()
+	table, err := repository.tenancy.qualifiedTable(modelSchema)
+
+	if err != nil {
+		repository.latestError = err
+		panic("Repository[CreateFullTextIndex]: " + err.Error())
+	}
+
+	resolved := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		field := modelSchema.LookUpField(column)
+
+		if field == nil || field.DBName == "" {
+			panic("Repository[CreateFullTextIndex]: Unknown column " + column)
+		}
+
+		resolved = append(resolved, field.DBName)
+	}
+
+	var statements []string
+
+	switch query.Dialector.Name() {
+	case "sqlite":
+		if len(modelSchema.PrimaryFields) != 1 {
+			return fmt.Errorf("%w for models without a single primary key", ErrFullTextNotSupported)
+		}
+
+		statements = sqliteFullTextIndex(query.Statement, table, modelSchema.PrimaryFields[0].DBName, resolved)
+	case "postgres":
+		statements = []string{fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s)",
+			query.Statement.Quote(strings.ReplaceAll(table, ".", "_")+"_fulltext"),
+			query.Statement.Quote(clause.Table{Name: table}),
+			fullTextDocument(query.Statement, "", resolved),
+		)}
+	case "mysql":
+		quoted := make([]string, 0, len(resolved))
+
+		for _, column := range resolved {
+			quoted = append(quoted, query.Statement.Quote(column))
+		}
+
+		statements = []string{fmt.Sprintf(
+			"ALTER TABLE %s ADD FULLTEXT INDEX %s (%s)",
+			query.Statement.Quote(clause.Table{Name: table}),
+			query.Statement.Quote(strings.ReplaceAll(table, ".", "_")+"_fulltext"),
+			strings.Join(quoted, ", "),
+		)}
+	default:
+		return fmt.Errorf("%w by %s", ErrFullTextNotSupported, query.Dialector.Name())
+	}
+
+	return repository.connection.Transaction(func(transaction *gorm.DB) error {
+		for _, statement := range statements {
+			if err := transaction.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// sqliteFullTextIndex
+// Compiles the statements creating an FTS5 table keyed by the primary
+// key, the triggers keeping it in sync with the table of the model
+// and the statements filling it with the existing rows. Triggers live
+// in the schema of the table and refer to tables without a schema,
+// as SQLite does not allow them to be qualified
+func sqliteFullTextIndex(statement *gorm.Statement, table string, key string, columns []string) []string {
+	name := table[strings.LastIndex(table, ".")+1:]
+	index := statement.Quote(clause.Table{Name: fullTextTable(table)})
+	source := statement.Quote(clause.Table{Name: table})
+	triggerIndex := statement.Quote(clause.Table{Name: fullTextTable(name)})
+	triggerSource := statement.Quote(clause.Table{Name: name})
+	trigger := func(event string) string {
+		return statement.Quote(clause.Table{Name: fullTextTable(table) + "_" + event})
+	}
+
+	quoted := []string{statement.Quote(key)}
+	newValues := []string{"new." + statement.Quote(key)}
+
+	for _, column := range columns {
+		quoted = append(quoted, statement.Quote(column))
+		newValues = append(newValues, "new."+statement.Quote(column))
+	}
+
+	names := strings.Join(quoted, ", ")
+	definitions := statement.Quote(key) + " UNINDEXED, " + strings.Join(quoted[1:], ", ")
+	insert := fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s);", triggerIndex, names, strings.Join(newValues, ", "))
+	remove := fmt.Sprintf("DELETE FROM %s WHERE %s = old.%s;", triggerIndex, statement.Quote(key), statement.Quote(key))
+
+	return []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s)", index, definitions),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER INSERT ON %s BEGIN %s END", trigger("insert"), triggerSource, insert),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER DELETE ON %s BEGIN %s END", trigger("delete"), triggerSource, remove),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE ON %s BEGIN %s %s END", trigger("update"), triggerSource, remove, insert),
+		fmt.Sprintf("DELETE FROM %s", index),
+		fmt.Sprintf("INSERT INTO %s(%s) SELECT %s FROM %s", index, names, names, source),
+	}
+}
+
+// fullTextDocument
+// Compiles the tsvector searched on Postgres
+func fullTextDocument(statement *gorm.Statement, table string, columns []string) string {
+	parts := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		parts = append(parts, "coalesce("+statement.Quote(clause.Column{Table: table, Name: column})+", '')")
+	}
+
+	return "to_tsvector('" + fullTextConfiguration + "', " + strings.Join(parts, " || ' ' || ") + ")"
+}
+
+// fullTextKey
+// Gets the primary key column the FTS5 table of a model is keyed by
+func fullTextKey(query *gorm.DB, model any) string {
+	modelSchema := parseSchema(query, model)
+
+	if len(modelSchema.PrimaryFields) != 1 {
+		panic("QueryBuilder[WhereFullText]: Full-text search requires a single primary key")
+	}
+
+	return modelSchema.PrimaryFields[0].DBName
+}
+
+// fullTextTable
+// Gets the name of the FTS5 table indexing a table on SQLite
+func fullTextTable(table string) string {
+	return table + "_fts"
+}
@@ -0,0 +1,148 @@
+package Repository
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"reflect"
+)
+
+// Operation
+// Describes a terminal call of a repository or query builder, or a transaction
+type Operation struct {
+	// The method called, like "QueryBuilder[Get]" or "Repository[Create]"
+	Method string
+
+	// The name of the model operated on. Empty for transactions
+	Model string
+}
+
+// Instrumentation
+// Observes operations, for instance by emitting traces and metrics.
+// Start is called when an operation begins and returns the context
+// the operation runs in, along with a function called with the error
+// of the operation, or nil, once it has finished
+type Instrumentation interface {
+	Start(ctx context.Context, operation Operation) (context.Context, func(err error))
+}
+
+// NoopInstrumentation
+// Instrumentation observing nothing. Used when none is configured
+type NoopInstrumentation struct{}
+
+// Start
+// Returns the context as is and a finish function doing nothing
+func (NoopInstrumentation) Start(ctx context.Context, _ Operation) (context.Context, func(err error)) {
+	return ctx, func(error) {}
+}
+
+// instrumentationOf
+// Gets the instrumentation of a configuration, defaulting to no-op
+func instrumentationOf(config *Config) Instrumentation {
+	if config.Instrumentation == nil {
+		return NoopInstrumentation{}
+	}
+
+	return config.Instrumentation
+}
+
+// startOperation
+// Starts an operation and returns the context it runs in, along
+// with the function finishing it
+func startOperation(instrumentation Instrumentation, ctx context.Context, method string, model any) (context.Context, func(err error)) {
+	if instrumentation == nil {
+		instrumentation = NoopInstrumentation{}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return instrumentation.Start(ctx, Operation{Method: method, Model: operationModel(model)})
+}
+
+// finishOperation
+// Finishes an operation with the error returned, if any. Panics
+// recovered from the operation are finished as errors and re-panicked
+func finishOperation(finish func(err error), err *error, recovered any) {
+	if recovered != nil {
+		finish(fmt.Errorf("%v", recovered))
+		panic(recovered)
+	}
+
+	if err != nil {
+		finish(*err)
+
+		return
+	}
+
+	finish(nil)
+}
+
+// operationModel
+// Gets the name of the model type operated on
+func operationModel(model any) string {
+	if model == nil {
+		return ""
+	}
+
+	modelType := reflect.TypeOf(model)
+
+	for modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+
+	return modelType.Name()
+}
+
+// observe
+// Starts an operation of the repository and returns a copy of the repository
+// running its queries in the context of the operation, along with the function
+// finishing it, meant to be deferred. The repository itself is left as is, as
+// it may be used by other goroutines. Errors recorded by the copy are recorded
+// on the repository once the operation has finished
+func (repository *Repository[T]) observe(method string) (*Repository[T], func(err *error)) {
+	original := repository.connection.Statement.Context
+	ctx, finish := startOperation(repository.instrumentation, original, "Repository["+method+"]", repository.model)
+
+	observed := *repository
+
+	if ctx != original {
+		observed.connection = repository.connection.WithContext(ctx)
+		observed.readers = make([]*gorm.DB, 0, len(repository.readers))
+
+		for _, reader := range repository.readers {
+			observed.readers = append(observed.readers, reader.WithContext(ctx))
+		}
+	}
+
+	latestError := repository.latestError
+
+	return &observed, func(err *error) {
+		if observed.latestError != latestError {
+			repository.latestError = observed.latestError
+		}
+
+		finishOperation(finish, err, recover())
+	}
+}
+
+// observe
+// Starts an operation of the query builder and returns the function finishing
+// it, meant to be deferred. The queries of the operation run in its context
+func (builder *QueryBuilder[T]) observe(method string) func(err *error) {
+	original := builder.query.Statement.Context
+	ctx, finish := startOperation(builder.instrumentation, original, "QueryBuilder["+method+"]", builder.model)
+
+	if ctx != original {
+		builder.query = builder.query.WithContext(ctx)
+	}
+
+	return func(err *error) {
+		if ctx != original {
+			builder.query = builder.query.WithContext(original)
+		}
+
+		finishOperation(finish, err, recover())
+	}
+}
@@ -0,0 +1,252 @@
+package Repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// jsonPathSegment
+// Matches the keys and indexes allowed in JSON paths
+var jsonPathSegment = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// arrayIndex
+// Matches JSON path segments indexing arrays
+var arrayIndex = regexp.MustCompile(`^[0-9]+$`)
+
+// JSON
+// Stores a value of any type as JSON in a single column
+type JSON[T any] struct {
+	Data T
+}
+
+// NewJSON
+// Wraps a value to be stored as JSON
+func NewJSON[T any](data T) JSON[T] {
+	return JSON[T]{Data: data}
+}
+
+// Value
+// Encodes the value when writing it to the database
+func (value JSON[T]) Value() (driver.Value, error) {
+	encoded, err := json.Marshal(value.Data)
+
+	return string(encoded), err
+}
+
+// Scan
+// Decodes the value when reading it from the database
+func (value *JSON[T]) Scan(source any) error {
+	switch typed := source.(type) {
+	case nil:
+		var empty T
+		value.Data = empty
+
+		return nil
+	case []byte:
+		return json.Unmarshal(typed, &value.Data)
+	case string:
+		return json.Unmarshal([]byte(typed), &value.Data)
+	default:
+		return fmt.Errorf("JSON[Scan]: Unsupported source %T", source)
+	}
+}
+
+// MarshalJSON
+// Encodes the wrapped value, leaving out the wrapper
+func (value JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(value.Data)
+}
+
+// UnmarshalJSON
+// Decodes into the wrapped value
+func (value *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &value.Data)
+}
+
+// GormDataType
+// The general data type of JSON columns
+func (JSON[T]) GormDataType() string {
+	return "json"
+}
+
+// GormDBDataType
+// The column type of JSON columns for the dialect migrated
+func (JSON[T]) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "JSONB"
+	case "mysql":
+		return "JSON"
+	default:
+		return "TEXT"
+	}
+}
+
+// GormValue
+// Casts the value to JSON on MySQL, keeping strings from being stored as text
+func (value JSON[T]) GormValue(_ context.Context, db *gorm.DB) clause.Expr {
+	encoded, _ := json.Marshal(value.Data)
+
+	if db.Dialector.Name() == "mysql" {
+		return clause.Expr{SQL: "CAST(? AS JSON)", Vars: []any{string(encoded)}}
+	}
+
+	return clause.Expr{SQL: "?", Vars: []any{string(encoded)}}
+}
+
+// WhereJSON
+// Filters rows by comparing a value inside a JSON column, addressed by a
+// path like meta->address->city, using one of =, !=, <>, <, <=, > or >=
+func (builder *QueryBuilder[T]) WhereJSON(path string, operator string, value any) *QueryBuilder[T] {
+	if !comparisonOperators[operator] {
+		panic("QueryBuilder[WhereJSON]: Invalid operator " + operator)
+	}
+
+	extracted := builder.jsonExtract("WhereJSON", path)
+
+	if builder.query.Dialector.Name() == "postgres" {
+		extracted = postgresCast(extracted, value)
+	}
+
+	builder.query = builder.query.Where(extracted+" "+operator+" ?", value)
+
+	return builder
+}
+
+// WhereJSONContains
+// Filters rows by a JSON array, addressed by a path like meta->tags,
+// containing a value, or all values when passing a slice
+func (builder *QueryBuilder[T]) WhereJSONContains(path string, value any) *QueryBuilder[T] {
+	column, segments := builder.jsonPath("WhereJSONContains", path)
+	quoted := builder.query.Statement.Quote(column)
+
+	switch builder.query.Dialector.Name() {
+	case "postgres":
+		encoded, _ := json.Marshal(value)
+
+		builder.query = builder.query.Where(quoted+" #> '"+postgresPath(segments)+"' @> ?::jsonb", string(encoded))
+	case "mysql":
+		encoded, _ := json.Marshal(value)
+
+		builder.query = builder.query.Where("JSON_CONTAINS("+quoted+", ?, '"+standardPath(segments)+"')", string(encoded))
+	default:
+		values := reflect.ValueOf(value)
+
+		if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+			values = reflect.ValueOf([]any{value})
+		}
+
+		for index := 0; index < values.Len(); index++ {
+			builder.query = builder.query.Where(
+				"EXISTS (SELECT 1 FROM json_each("+quoted+", '"+standardPath(segments)+"') WHERE json_each.value = ?)",
+				values.Index(index).Interface(),
+			)
+		}
+	}
+
+	return builder
+}
+
+// WhereJSONLength
+// Filters rows by comparing the length of a JSON array, addressed by
+// a path like meta->tags, using one of =, !=, <>, <, <=, > or >=
+func (builder *QueryBuilder[T]) WhereJSONLength(path string, operator string, length int) *QueryBuilder[T] {
+	if !comparisonOperators[operator] {
+		panic("QueryBuilder[WhereJSONLength]: Invalid operator " + operator)
+	}
+
+	column, segments := builder.jsonPath("WhereJSONLength", path)
+	quoted := builder.query.Statement.Quote(column)
+
+	var measured string
+
+	switch builder.query.Dialector.Name() {
+	case "postgres":
+		measured = "jsonb_array_length(" + quoted + " #> '" + postgresPath(segments) + "')"
+	case "mysql":
+		measured = "JSON_LENGTH(" + quoted + ", '" + standardPath(segments) + "')"
+	default:
+		measured = "json_array_length(" + quoted + ", '" + standardPath(segments) + "')"
+	}
+
+	builder.query = builder.query.Where(measured+" "+operator+" ?", length)
+
+	return builder
+}
+
+// jsonExtract
+// Compiles the extraction of a value inside a JSON column as SQL
+func (builder *QueryBuilder[T]) jsonExtract(method string, path string) string {
+	column, segments := builder.jsonPath(method, path)
+	quoted := builder.query.Statement.Quote(column)
+
+	switch builder.query.Dialector.Name() {
+	case "postgres":
+		return "(" + quoted + " #>> '" + postgresPath(segments) + "')"
+	case "mysql":
+		return "JSON_UNQUOTE(JSON_EXTRACT(" + quoted + ", '" + standardPath(segments) + "'))"
+	default:
+		return "json_extract(" + quoted + ", '" + standardPath(segments) + "')"
+	}
+}
+
+// jsonPath
+// Splits a path like meta->address->city into the column and the
+// keys inside it. Keys are only letters, digits and underscores,
+// which is what allows them to be written into the SQL directly
+func (builder *QueryBuilder[T]) jsonPath(method string, path string) (clause.Column, []string) {
+	parts := strings.Split(path, "->")
+
+	for _, segment := range parts[1:] {
+		if !jsonPathSegment.MatchString(segment) {
+			panic("QueryBuilder[" + method + "]: Invalid JSON path " + path)
+		}
+	}
+
+	return builder.resolveColumn(method, parts[0], false), parts[1:]
+}
+
+// standardPath
+// Formats the keys of a JSON path for SQLite and MySQL
+func standardPath(segments []string) string {
+	path := "$"
+
+	for _, segment := range segments {
+		if arrayIndex.MatchString(segment) {
+			path += "[" + segment + "]"
+		} else {
+			path += "." + segment
+		}
+	}
+
+	return path
+}
+
+// postgresPath
+// Formats the keys of a JSON path for Postgres
+func postgresPath(segments []string) string {
+	return "{" + strings.Join(segments, ",") + "}"
+}
+
+// postgresCast
+// Casts the text extracted from JSON on Postgres to the type compared to
+func postgresCast(extracted string, value any) string {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return extracted + "::numeric"
+	case reflect.Bool:
+		return extracted + "::boolean"
+	default:
+		return extracted
+	}
+}
@@ -0,0 +1,229 @@
+package Repository
+
+import (
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"strings"
+)
+
+// Join
+// Adds an inner join on a table using a raw condition
+func (builder *QueryBuilder[T]) Join(table string, condition string, args ...any) *QueryBuilder[T] {
+	return builder.join("JOIN", table, condition, args...)
+}
+
+// LeftJoin
+// Adds a left join on a table using a raw condition
+func (builder *QueryBuilder[T]) LeftJoin(table string, condition string, args ...any) *QueryBuilder[T] {
+	return builder.join("LEFT JOIN", table, condition, args...)
+}
+
+// RightJoin
+// Adds a right join on a table using a raw condition
+func (builder *QueryBuilder[T]) RightJoin(table string, condition string, args ...any) *QueryBuilder[T] {
+	return builder.join("RIGHT JOIN", table, condition, args...)
+}
+
+// CrossJoin
+// Adds a cross join on a table
+func (builder *QueryBuilder[T]) CrossJoin(table string) *QueryBuilder[T] {
+	builder.selectModelColumns()
+	builder.query = builder.query.Joins("CROSS JOIN ?", clause.Table{Name: table})
+
+	return builder
+}
+
+// JoinRelation
+// Adds an inner join on a relationship of the model, named the same
+// way as relationships returned by With(). The ON clause is derived
+// from the relationship declared on the model
+func (builder *QueryBuilder[T]) JoinRelation(relation string) *QueryBuilder[T] {
+	return builder.joinRelation("JoinRelation", "JOIN", relation)
+}
+
+// LeftJoinRelation
+// Adds a left join on a relationship of the model, named the same
+// way as relationships returned by With()
+func (builder *QueryBuilder[T]) LeftJoinRelation(relation string) *QueryBuilder[T] {
+	return builder.joinRelation("LeftJoinRelation", "LEFT JOIN", relation)
+}
+
+// join
+// Adds a join on a table using a raw condition
+func (builder *QueryBuilder[T]) join(kind string, table string, condition string, args ...any) *QueryBuilder[T] {
+	builder.selectModelColumns()
+
+	arguments := append([]any{clause.Table{Name: table}}, args...)
+	builder.query = builder.query.Joins(kind+" ? ON "+condition, arguments...)
+
+	return builder
+}
+
+// joinRelation
+// Adds a join on a relationship of the model. Many to many
+// relationships are joined through their join table
+func (builder *QueryBuilder[T]) joinRelation(method string, kind string, name string) *QueryBuilder[T] {
+	modelSchema := parseSchema(builder.query, builder.model)
+	relationship, exists := modelSchema.Relationships.Relations[name]
+
+	if !exists {
+		panic("QueryBuilder[" + method + "]: Model has no relationship named " + name)
+	}
+
+	builder.selectModelColumns()
+
+	ownerTable := modelSchema.Table
+
+	if builder.query.Statement.Table != "" {
+		ownerTable = builder.query.Statement.Table
+	}
+
+	// Relationships to the same table are joined under the relationship name
+	relatedTable, relatedName, tenantCondition, tenantArguments := builder.relatedTable(method, relationship)
+
+	if relationship.FieldSchema.Table == modelSchema.Table {
+		relatedTable.Alias = relationship.Name
+		relatedName = relationship.Name
+		tenantCondition, tenantArguments = builder.tenantCondition(method, relationship, relatedName)
+	}
+
+	if relationship.JoinTable != nil {
+		joinTable := relationship.JoinTable.Table
+
+		ownConditions, ownArguments := joinConditions(relationship.References, true, ownerTable, joinTable)
+		relatedConditions, relatedArguments := joinConditions(relationship.References, false, relatedName, joinTable)
+
+		if tenantCondition != "" {
+			relatedConditions += " AND " + tenantCondition
+			relatedArguments = append(relatedArguments, tenantArguments...)
+		}
+
+		builder.query = builder.query.
+			Joins(kind+" ? ON "+ownConditions, append([]any{clause.Table{Name: joinTable}}, ownArguments...)...).
+			Joins(kind+" ? ON "+relatedConditions, append([]any{relatedTable}, relatedArguments...)...)
+
+		return builder
+	}
+
+	var conditions []string
+	var arguments []any
+
+	for _, reference := range relationship.References {
+		// The primary key belongs to the model if it owns it,
+		// and the foreign key to the related table, and vice versa
+		primaryTable, foreignTable := relatedName, ownerTable
+
+		if reference.OwnPrimaryKey || reference.PrimaryKey == nil {
+			primaryTable, foreignTable = ownerTable, relatedName
+		}
+
+		condition, conditionArguments := joinCondition(reference, primaryTable, foreignTable)
+		conditions = append(conditions, condition)
+		arguments = append(arguments, conditionArguments...)
+	}
+
+	if tenantCondition != "" {
+		conditions = append(conditions, tenantCondition)
+		arguments = append(arguments, tenantArguments...)
+	}
+
+	builder.query = builder.query.Joins(kind+" ? ON "+strings.Join(conditions, " AND "), append([]any{relatedTable}, arguments...)...)
+
+	return builder
+}
+
+// relatedTable
+// Gets the table a relationship is joined on, along with the name it is
+// referred to by and the condition scoping it to the tenant, if any.
+// Models kept apart by table are joined on the table of the tenant
+func (builder *QueryBuilder[T]) relatedTable(method string, relationship *schema.Relationship) (clause.Table, string, string, []any) {
+	relatedSchema := relationship.FieldSchema
+	scope := builder.tenancy
+
+	if !scope.disabled && scope.strategy != TenancyColumn && scope.tenant != nil {
+		table, name, err := scope.table(relatedSchema)
+
+		if err != nil {
+			panic("QueryBuilder[" + method + "]: " + err.Error())
+		}
+
+		if table != name {
+			return clause.Table{Name: table, Alias: name}, name, "", nil
+		}
+
+		return clause.Table{Name: table}, name, "", nil
+	}
+
+	condition, arguments := builder.tenantCondition(method, relationship, relatedSchema.Table)
+
+	return clause.Table{Name: relatedSchema.Table}, relatedSchema.Table, condition, arguments
+}
+
+// tenantCondition
+// Gets the join condition scoping a related model with a tenant column to the tenant
+func (builder *QueryBuilder[T]) tenantCondition(method string, relationship *schema.Relationship, relatedName string) (string, []any) {
+	scope := builder.tenancy
+
+	if scope.disabled || scope.strategy != TenancyColumn {
+		return "", nil
+	}
+
+	relatedSchema := relationship.FieldSchema
+	field := tenantField(relatedSchema, reflect.New(relatedSchema.ModelType).Interface())
+
+	if field == nil {
+		return "", nil
+	}
+
+	if scope.tenant == nil {
+		panic("QueryBuilder[" + method + "]: " + ErrMissingTenant.Error())
+	}
+
+	return "? = ?", []any{clause.Column{Table: relatedName, Name: field.DBName}, scope.tenant}
+}
+
+// joinConditions
+// Builds the ON clause joining a join table to either the
+// model or the related table of a many to many relationship
+func joinConditions(references []*schema.Reference, own bool, primaryTable string, joinTable string) (string, []any) {
+	var conditions []string
+	var arguments []any
+
+	for _, reference := range references {
+		if reference.OwnPrimaryKey != own {
+			continue
+		}
+
+		condition, conditionArguments := joinCondition(reference, primaryTable, joinTable)
+		conditions = append(conditions, condition)
+		arguments = append(arguments, conditionArguments...)
+	}
+
+	return strings.Join(conditions, " AND "), arguments
+}
+
+// joinCondition
+// Builds a single condition of an ON clause from a reference.
+// Polymorphic references compare the foreign key to a value
+func joinCondition(reference *schema.Reference, primaryTable string, foreignTable string) (string, []any) {
+	foreignKey := clause.Column{Table: foreignTable, Name: reference.ForeignKey.DBName}
+
+	if reference.PrimaryKey == nil {
+		return "? = ?", []any{foreignKey, reference.PrimaryValue}
+	}
+
+	return "? = ?", []any{foreignKey, clause.Column{Table: primaryTable, Name: reference.PrimaryKey.DBName}}
+}
+
+// selectModelColumns
+// Joined tables share column names with the model, so unless
+// columns are selected explicitly only those of the model are
+func (builder *QueryBuilder[T]) selectModelColumns() {
+	if len(builder.query.Statement.Selects) > 0 || len(builder.selects) > 0 {
+		return
+	}
+
+	builder.selects = []clause.Expr{builder.modelColumns()}
+	builder.applySelects()
+}
@@ -0,0 +1,63 @@
+package Repository
+
+import (
+	"gorm.io/gorm/clause"
+)
+
+// LockForUpdate
+// Locks the selected rows for updating until the transaction ends
+func (builder *QueryBuilder[T]) LockForUpdate() *QueryBuilder[T] {
+	return builder.applyLock("LockForUpdate", clause.LockingStrengthUpdate, "")
+}
+
+// SharedLock
+// Locks the selected rows against updates until the transaction ends
+func (builder *QueryBuilder[T]) SharedLock() *QueryBuilder[T] {
+	return builder.applyLock("SharedLock", clause.LockingStrengthShare, "")
+}
+
+// SkipLocked
+// Skips rows locked by other transactions. Implies LockForUpdate()
+// if no other lock has been applied to the query
+func (builder *QueryBuilder[T]) SkipLocked() *QueryBuilder[T] {
+	return builder.applyLock("SkipLocked", "", clause.LockingOptionsSkipLocked)
+}
+
+// NoWait
+// Fails right away instead of waiting for rows locked by other
+// transactions. Implies LockForUpdate() if no other lock has
+// been applied to the query
+func (builder *QueryBuilder[T]) NoWait() *QueryBuilder[T] {
+	return builder.applyLock("NoWait", "", clause.LockingOptionsNoWait)
+}
+
+// applyLock
+// Adds a locking clause to the query. Locks only make sense inside
+// a transaction, so we bail if the query is not part of one. SQLite
+// has no row locks, as the whole database is locked by the
+// transaction, so there the clause is left out
+func (builder *QueryBuilder[T]) applyLock(method string, strength string, options string) *QueryBuilder[T] {
+	if !inTransaction(builder.query) {
+		panic("QueryBuilder[" + method + "]: Locks can only be used inside a transaction")
+	}
+
+	if strength != "" {
+		builder.lock.Strength = strength
+	}
+
+	if options != "" {
+		builder.lock.Options = options
+	}
+
+	if builder.lock.Strength == "" {
+		builder.lock.Strength = clause.LockingStrengthUpdate
+	}
+
+	if builder.query.Dialector.Name() == "sqlite" {
+		return builder
+	}
+
+	builder.query = builder.query.Clauses(builder.lock)
+
+	return builder
+}
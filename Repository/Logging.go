@@ -0,0 +1,348 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redactTag
+// The struct tag value marking a field as sensitive
+const redactTag = "redact"
+
+// redactedValue
+// Replaces bindings of sensitive columns in query logs
+const redactedValue = "[REDACTED]"
+
+// startedKey
+// The statement setting holding the moment a query started
+const startedKey = "repository:started"
+
+// insertColumns
+// Matches the columns of INSERT statements
+var insertColumns = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+\S+\s*\(([^)]*)\)\s*VALUES`)
+
+// comparedColumn
+// Matches the column a binding is compared to or assigned to,
+// skipping earlier bindings of the same IN list
+var comparedColumn = regexp.MustCompile(`(?i)([A-Za-z_][A-Za-z0-9_]*)["\x60\]]?\s*(?:=|!=|<>|<=|>=|<|>|NOT\s+LIKE|LIKE|NOT\s+IN|IN)\s*\(?[\s?$0-9,]*$`)
+
+// loggedConnections
+// The callback processors query logging callbacks have been registered on.
+// Sessions and transactions copy the config of a connection but share its
+// callbacks, so registrations are tracked by the callbacks themselves
+var loggedConnections sync.Map
+
+type queryLoggerContextKey struct{}
+
+type queryLogger struct {
+	logger    *slog.Logger
+	threshold time.Duration
+	redacted  map[string]bool
+}
+
+// newQueryLogger
+// Creates the query logging settings of a configuration. Returns nil
+// when neither a logger nor a slow query threshold is configured
+func newQueryLogger(config *Config) *queryLogger {
+	if config.Logger == nil && config.SlowQueryThreshold <= 0 {
+		return nil
+	}
+
+	redacted := make(map[string]bool, len(config.RedactedColumns))
+
+	for _, column := range config.RedactedColumns {
+		redacted[strings.ToLower(column)] = true
+	}
+
+	return &queryLogger{
+		logger:    config.Logger,
+		threshold: config.SlowQueryThreshold,
+		redacted:  redacted,
+	}
+}
+
+// instrument
+// Prepares a connection for query logging by registering the
+// logging callbacks and carrying the settings in its context
+func (settings *queryLogger) instrument(connection *gorm.DB) *gorm.DB {
+	if settings == nil || connection == nil {
+		return connection
+	}
+
+	registerQueryLogger(connection)
+
+	return connection.WithContext(settings.context(connection.Statement.Context))
+}
+
+// context
+// Carries the settings in a context
+func (settings *queryLogger) context(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if settings == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, queryLoggerContextKey{}, settings)
+}
+
+// registerQueryLogger
+// Registers the callbacks timing and logging queries, once per callback processor
+func registerQueryLogger(connection *gorm.DB) {
+	callbacks := connection.Callback()
+
+	if _, registered := loggedConnections.LoadOrStore(callbacks, true); registered {
+		return
+	}
+
+	_ = callbacks.Create().Before("*").Register("repository:before_create", startQuery)
+	_ = callbacks.Create().After("*").Register("repository:after_create", logQuery)
+	_ = callbacks.Query().Before("*").Register("repository:before_query", startQuery)
+	_ = callbacks.Query().After("*").Register("repository:after_query", logQuery)
+	_ = callbacks.Update().Before("*").Register("repository:before_update", startQuery)
+	_ = callbacks.Update().After("*").Register("repository:after_update", logQuery)
+	_ = callbacks.Delete().Before("*").Register("repository:before_delete", startQuery)
+	_ = callbacks.Delete().After("*").Register("repository:after_delete", logQuery)
+	_ = callbacks.Row().Before("*").Register("repository:before_row", startQuery)
+	_ = callbacks.Row().After("*").Register("repository:after_row", logQuery)
+	_ = callbacks.Raw().Before("*").Register("repository:before_raw", startQuery)
+	_ = callbacks.Raw().After("*").Register("repository:after_raw", logQuery)
+}
+
+// startQuery
+// Records the moment a query starts
+func startQuery(query *gorm.DB) {
+	if settingsOf(query) != nil {
+		query.InstanceSet(startedKey, time.Now())
+	}
+}
+
+// logQuery
+// Logs a finished query. Failed queries are logged as errors and queries
+// slower than the threshold as warnings. Without a logger, only slow
+// queries are logged, using the default logger
+func logQuery(query *gorm.DB) {
+	settings := settingsOf(query)
+
+	if settings == nil || query.DryRun {
+		return
+	}
+
+	started, exists := query.InstanceGet(startedKey)
+
+	if !exists {
+		return
+	}
+
+	duration := time.Since(started.(time.Time))
+	failed := query.Error != nil && !errors.Is(query.Error, gorm.ErrRecordNotFound)
+	slow := settings.threshold > 0 && duration >= settings.threshold
+	logger := settings.logger
+
+	if logger == nil {
+		if !slow {
+			return
+		}
+
+		logger = slog.Default()
+	}
+
+	sql := query.Statement.SQL.String()
+	bindings := settings.redact(query.Statement, sql)
+	attributes := []any{
+		slog.String("sql", query.Dialector.Explain(sql, bindings...)),
+		slog.Duration("duration", duration),
+		slog.Int64("rows_affected", query.RowsAffected),
+		slog.String("caller", caller()),
+		slog.String("model", modelName(query.Statement)),
+	}
+
+	ctx := query.Statement.Context
+
+	switch {
+	case failed:
+		logger.ErrorContext(ctx, "Repository: Query failed", append(attributes, slog.String("error", query.Error.Error()))...)
+	case slow:
+		logger.WarnContext(ctx, "Repository: Slow query", append(attributes, slog.Duration("threshold", settings.threshold))...)
+	default:
+		logger.InfoContext(ctx, "Repository: Query", attributes...)
+	}
+}
+
+// settingsOf
+// Gets the query logging settings carried by the context of a query
+func settingsOf(query *gorm.DB) *queryLogger {
+	if query.Statement.Context == nil {
+		return nil
+	}
+
+	settings, _ := query.Statement.Context.Value(queryLoggerContextKey{}).(*queryLogger)
+
+	return settings
+}
+
+// redact
+// Replaces the bindings of sensitive columns, configured by name or tagged
+// with `repository:"redact"`. Bindings are matched to columns by the column
+// list of INSERT statements, or the column they are compared to otherwise
+func (settings *queryLogger) redact(statement *gorm.Statement, sql string) []any {
+	sensitive := func(column string) bool {
+		if settings.redacted[strings.ToLower(column)] {
+			return true
+		}
+
+		if statement.Schema == nil {
+			return false
+		}
+
+		field := statement.Schema.LookUpField(column)
+
+		return field != nil && field.Tag.Get("repository") == redactTag
+	}
+
+	bindings := append([]any{}, statement.Vars...)
+
+	var columns []string
+	valuesStart := -1
+
+	if match := insertColumns.FindStringSubmatchIndex(sql); match != nil {
+		for _, column := range strings.Split(sql[match[2]:match[3]], ",") {
+			columns = append(columns, strings.Trim(strings.TrimSpace(column), "\"\x60[]"))
+		}
+
+		valuesStart = match[1]
+	}
+
+	for index, position := range placeholders(sql) {
+		if index >= len(bindings) {
+			break
+		}
+
+		column := ""
+
+		if valuesStart >= 0 && position > valuesStart && len(columns) > 0 {
+			column = columns[index%len(columns)]
+		} else if match := comparedColumn.FindStringSubmatch(sql[:position]); match != nil {
+			column = match[1]
+		}
+
+		if column != "" && sensitive(column) {
+			bindings[index] = redactedValue
+		}
+	}
+
+	return bindings
+}
+
+// placeholders
+// Finds the positions of bindings in SQL, written as ? or $1, $2 and so
+// on depending on the dialect, skipping anything inside quotes
+func placeholders(sql string) []int {
+	var positions []int
+	var quote byte
+
+	for index := 0; index < len(sql); index++ {
+		character := sql[index]
+
+		switch {
+		case quote != 0:
+			if character == quote {
+				quote = 0
+			}
+		case character == '\'' || character == '"' || character == '\x60':
+			quote = character
+		case character == '?':
+			positions = append(positions, index)
+		case character == '$' && index+1 < len(sql) && sql[index+1] >= '0' && sql[index+1] <= '9':
+			end := index + 1
+
+			for end < len(sql) && sql[end] >= '0' && sql[end] <= '9' {
+				end++
+			}
+
+			number, _ := strconv.Atoi(sql[index+1 : end])
+
+			for len(positions) < number {
+				positions = append(positions, index)
+			}
+		}
+	}
+
+	return positions
+}
+
+// caller
+// Finds the method of the repository, query builder or unit of work the
+// query was executed by, being the outermost method of the package on
+// the call stack below the code using it
+func caller() string {
+	counters := make([]uintptr, 64)
+	frames := runtime.CallersFrames(counters[:runtime.Callers(3, counters)])
+	prefix := reflect.TypeOf(Config{}).PkgPath() + "."
+	found := ""
+	left := false
+
+	for {
+		frame, more := frames.Next()
+		inside := strings.HasPrefix(frame.Function, prefix)
+
+		switch {
+		case !left:
+			left = !inside
+		case inside:
+			found = strings.TrimPrefix(frame.Function, prefix)
+		case found != "":
+			return callerName(found)
+		}
+
+		if !more {
+			return callerName(found)
+		}
+	}
+}
+
+// callerName
+// Shortens function names like (*QueryBuilder[...]).Get to QueryBuilder[Get]
+func callerName(function string) string {
+	if function == "" {
+		return ""
+	}
+
+	receiver, method, isMethod := strings.Cut(function, ").")
+
+	if !isMethod {
+		name, _, _ := strings.Cut(function, "[")
+
+		return name
+	}
+
+	receiver = strings.TrimPrefix(receiver, "(*")
+	receiver, _, _ = strings.Cut(receiver, "[")
+
+	return receiver + "[" + method + "]"
+}
+
+// modelName
+// Gets the name of the model a query is executed for
+func modelName(statement *gorm.Statement) string {
+	if statement.Schema != nil {
+		return statement.Schema.Name
+	}
+
+	if statement.Model != nil {
+		return reflect.Indirect(reflect.ValueOf(statement.Model)).Type().String()
+	}
+
+	return ""
+}
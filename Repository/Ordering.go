@@ -0,0 +1,208 @@
+package Repository
+
+import (
+	"gorm.io/gorm/clause"
+	"regexp"
+	"strings"
+)
+
+// orderableColumn
+// Matches columns that can be ordered by, optionally qualified by a table
+var orderableColumn = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// selectedAlias
+// Matches the alias of a selected expression
+var selectedAlias = regexp.MustCompile(`(?i)\s+AS\s+([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+
+// OrderBy
+// Orders the results by a column of the model, an alias of a selected
+// expression or a path inside a JSON column like meta->address->city.
+// Direction is asc or desc, optionally followed by nulls first or
+// nulls last. Panics on unknown columns and invalid directions
+func (builder *QueryBuilder[T]) OrderBy(column string, direction string) *QueryBuilder[T] {
+	return builder.orderBy("OrderBy", column, direction)
+}
+
+// OrderByDesc
+// Orders the results by a column in descending order
+func (builder *QueryBuilder[T]) OrderByDesc(column string) *QueryBuilder[T] {
+	return builder.orderBy("OrderByDesc", column, "desc")
+}
+
+// Latest
+// Orders the results newest first, by created_at unless another column is given
+func (builder *QueryBuilder[T]) Latest(column ...string) *QueryBuilder[T] {
+	return builder.orderBy("Latest", timestampColumn(column), "desc")
+}
+
+// Oldest
+// Orders the results oldest first, by created_at unless another column is given
+func (builder *QueryBuilder[T]) Oldest(column ...string) *QueryBuilder[T] {
+	return builder.orderBy("Oldest", timestampColumn(column), "asc")
+}
+
+// InRandomOrder
+// Orders the results randomly
+func (builder *QueryBuilder[T]) InRandomOrder() *QueryBuilder[T] {
+	function := "RANDOM()"
+
+	switch builder.query.Dialector.Name() {
+	case "mysql":
+		function = "RAND()"
+	case "sqlserver":
+		function = "NEWID()"
+	}
+
+	return builder.addOrder(clause.OrderByColumn{Column: clause.Column{Name: function, Raw: true}})
+}
+
+// Reorder
+// Removes all orderings added so far, optionally replacing
+// them with an ordering by column in the given direction
+func (builder *QueryBuilder[T]) Reorder(columnAndDirection ...string) *QueryBuilder[T] {
+	builder.orders = nil
+	builder.rawOrders = nil
+	builder.applyOrders()
+
+	switch len(columnAndDirection) {
+	case 0:
+		return builder
+	case 1:
+		return builder.orderBy("Reorder", columnAndDirection[0], "asc")
+	default:
+		return builder.orderBy("Reorder", columnAndDirection[0], columnAndDirection[1])
+	}
+}
+
+// orderBy
+// Validates a column and direction and adds them to the orderings
+func (builder *QueryBuilder[T]) orderBy(method string, column string, direction string) *QueryBuilder[T] {
+	var orderColumn clause.Column
+
+	if strings.Contains(column, "->") {
+		orderColumn = clause.Column{Name: builder.jsonExtract(method, column), Raw: true}
+	} else {
+		orderColumn = builder.orderColumn(method, column)
+	}
+
+	words := strings.Fields(strings.ToUpper(direction))
+
+	if len(words) == 0 {
+		words = []string{"ASC"}
+	}
+
+	if words[0] != "ASC" && words[0] != "DESC" {
+		panic("QueryBuilder[" + method + "]: Invalid direction " + direction)
+	}
+
+	isNull := clause.Column{Name: builder.query.Statement.Quote(orderColumn) + " IS NULL", Raw: true}
+
+	switch {
+	case len(words) == 1:
+	case len(words) == 3 && words[1] == "NULLS" && words[2] == "FIRST":
+		builder.orders = append(builder.orders, clause.OrderByColumn{Column: isNull, Desc: true})
+	case len(words) == 3 && words[1] == "NULLS" && words[2] == "LAST":
+		builder.orders = append(builder.orders, clause.OrderByColumn{Column: isNull})
+	default:
+		panic("QueryBuilder[" + method + "]: Invalid direction " + direction)
+	}
+
+	return builder.addOrder(clause.OrderByColumn{Column: orderColumn, Desc: words[0] == "DESC"})
+}
+
+// addOrder
+// Adds an ordering and applies the orderings to the query
+func (builder *QueryBuilder[T]) addOrder(ordering clause.OrderByColumn) *QueryBuilder[T] {
+	builder.orders = append(builder.orders, ordering)
+	builder.applyOrders()
+
+	return builder
+}
+
+// applyOrders
+// Replaces the ORDER BY clause of the query with the tracked orderings
+func (builder *QueryBuilder[T]) applyOrders() {
+	builder.query = builder.query.Clauses()
+	delete(builder.query.Statement.Clauses, "ORDER BY")
+
+	if len(builder.orders) == 0 {
+		return
+	}
+
+	builder.query = builder.query.Clauses(clause.OrderBy{Columns: builder.orders})
+
+	if len(builder.rawOrders) > 0 {
+		orderClause := builder.query.Statement.Clauses["ORDER BY"]
+		orderClause.Builder = buildOrders(builder.rawOrders)
+		builder.query.Statement.Clauses["ORDER BY"] = orderClause
+	}
+}
+
+// orderColumn
+// Resolves a column to order by, which may also be the alias of a selected expression
+func (builder *QueryBuilder[T]) orderColumn(method string, column string) clause.Column {
+	return builder.resolveColumn(method, column, true)
+}
+
+// resolveColumn
+// Resolves a column passed by name. Columns of the model are resolved
+// through its schema, columns qualified by other tables and columns of
+// subqueries can not be and are only checked to be plain identifiers
+func (builder *QueryBuilder[T]) resolveColumn(method string, column string, allowAliases bool) clause.Column {
+	if !orderableColumn.MatchString(column) {
+		panic("QueryBuilder[" + method + "]: Invalid column " + column)
+	}
+
+	table := builder.table()
+	qualifier, name, qualified := strings.Cut(column, ".")
+
+	if !qualified {
+		qualifier, name = "", column
+	}
+
+	if builder.from != "" || (qualified && qualifier != table) {
+		return clause.Column{Table: qualifier, Name: name}
+	}
+
+	if field := parseSchema(builder.query, builder.model).LookUpField(name); field != nil && field.DBName != "" {
+		return clause.Column{Table: qualifier, Name: field.DBName}
+	}
+
+	if allowAliases && !qualified && builder.isSelectedAlias(name) {
+		return clause.Column{Name: name}
+	}
+
+	panic("QueryBuilder[" + method + "]: Unknown column " + column)
+}
+
+// isSelectedAlias
+// Determines if a name is the alias of a selected expression
+func (builder *QueryBuilder[T]) isSelectedAlias(name string) bool {
+	for _, selected := range builder.query.Statement.Selects {
+		if match := selectedAlias.FindStringSubmatch(selected); match != nil && match[1] == name {
+			return true
+		}
+	}
+
+	for _, expression := range builder.selects {
+		if !strings.HasSuffix(expression.SQL, "AS ?") || len(expression.Vars) == 0 {
+			continue
+		}
+
+		if alias, isColumn := expression.Vars[len(expression.Vars)-1].(clause.Column); isColumn && alias.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// timestampColumn
+// Gets the column used by Latest and Oldest
+func timestampColumn(column []string) string {
+	if len(column) > 0 {
+		return column[0]
+	}
+
+	return "created_at"
+}
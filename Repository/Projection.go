@@ -0,0 +1,41 @@
+package Repository
+
+import (
+	"github.com/nbj/go-collections/Collection"
+	"github.com/nbj/go-paginator/Paginator"
+	"gorm.io/gorm"
+)
+
+// Project
+// Executes the query and maps the selected columns onto another
+// type. Columns are matched to fields by name, or by the column
+// set with a `gorm:"column:..."` tag
+func Project[D any, T any](builder *QueryBuilder[T]) *Collection.Collection[D] {
+	defer builder.observe("Project")(nil)
+
+	var entries []D
+
+	if result := builder.compiled().Model(builder.model).Scan(&entries); result.Error != nil {
+		panic("QueryBuilder[Project]: " + result.Error.Error())
+	}
+
+	return Collection.Collect(entries)
+}
+
+// ProjectPaginate
+// Executes the query and paginates the results mapped onto another
+// type. The query is wrapped as a subquery, so totals are correct
+// for grouped and aggregated projections as well
+func ProjectPaginate[D any, T any](builder *QueryBuilder[T], page int, perPage int, path string) *Paginator.Paginator[D] {
+	defer builder.observe("ProjectPaginate")(nil)
+
+	projection := builder.query.
+		Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS projection", builder.compiled().Model(builder.model))
+
+	return Paginator.Paginate[D](projection, &Paginator.Boundaries{
+		Page:    page,
+		PerPage: perPage,
+		Path:    path,
+	})
+}
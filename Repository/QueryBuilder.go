@@ -1,25 +1,61 @@
 package Repository
 
 import (
-	"fmt"
 	"github.com/nbj/go-collections/Collection"
 	"github.com/nbj/go-paginator/Paginator"
 	"github.com/nbj/go-support/Support"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
 )
 
 type QueryBuilder[T any] struct {
-	query  *gorm.DB
-	model  *T
-	orders []string
+	query     *gorm.DB
+	writer    *gorm.DB
+	model     *T
+	orders    []clause.OrderByColumn
+	rawOrders []clause.Expr
+	selects   []clause.Expr
+	from      string
+	unions    []union[T]
+	ctes      []clause.Expr
+	recursive bool
+	relevance *clause.Expr
+	lock      clause.Locking
+	page      int
+	perPage   int
+
+	cache         Cache
+	remember      time.Duration
+	invalidations *invalidations
+	snapshots     *snapshots
+	timezone      *time.Location
+
+	instrumentation Instrumentation
+	tenancy         tenancy
 }
 
+// With
+// Preloads a relationship, possibly nested using dots, scoped to the tenant
 func (builder *QueryBuilder[T]) With(query string, args ...any) *QueryBuilder[T] {
-	builder.query = builder.query.Preload(query, args...)
+	builder.preload("With", query, args)
 
 	return builder
 }
 
+// preload
+// Preloads a relationship scoped to the tenant or dies trying
+func (builder *QueryBuilder[T]) preload(method string, name string, args []any) {
+	query, err := builder.tenancy.preload(builder.query, parseSchema(builder.query, builder.model), name, args)
+
+	if err != nil {
+		panic("QueryBuilder[" + method + "]: " + err.Error())
+	}
+
+	builder.query = query
+}
+
 func (builder *QueryBuilder[T]) Where(query any, args ...any) *QueryBuilder[T] {
 	builder.query = builder.query.Where(query, args...)
 
@@ -44,19 +80,123 @@ func (builder *QueryBuilder[T]) OrWhere(query any, args ...any) *QueryBuilder[T]
 	return builder
 }
 
-func (builder *QueryBuilder[T]) OrderBy(column string, direction string) *QueryBuilder[T] {
-	builder.query = builder.query.Order(fmt.Sprintf("%s %s", column, direction))
+// Select
+// Selects specific columns or expressions instead of all columns
+func (builder *QueryBuilder[T]) Select(columns ...string) *QueryBuilder[T] {
+	builder.selects = nil
+	builder.query = builder.query.Select(columns)
+
+	return builder
+}
+
+// Distinct
+// Only selects distinct rows, optionally over specific columns
+func (builder *QueryBuilder[T]) Distinct(columns ...string) *QueryBuilder[T] {
+	arguments := make([]any, 0, len(columns))
+
+	for _, column := range columns {
+		arguments = append(arguments, column)
+	}
+
+	builder.query = builder.query.Distinct(arguments...)
+
+	return builder
+}
+
+// GroupBy
+// Groups the rows by one or more columns
+func (builder *QueryBuilder[T]) GroupBy(columns ...string) *QueryBuilder[T] {
+	for _, column := range columns {
+		builder.query = builder.query.Group(column)
+	}
+
+	return builder
+}
+
+// Having
+// Filters grouped rows
+func (builder *QueryBuilder[T]) Having(query any, args ...any) *QueryBuilder[T] {
+	builder.query = builder.query.Having(query, args...)
 
 	return builder
 }
 
+// table
+// Gets the table the query selects from
+func (builder *QueryBuilder[T]) table() string {
+	if builder.from != "" {
+		return builder.from
+	}
+
+	if builder.query.Statement.Table != "" {
+		return builder.query.Statement.Table
+	}
+
+	return parseSchema(builder.query, builder.model).Table
+}
+
+// modelColumns
+// Gets an expression selecting all columns of the model
+func (builder *QueryBuilder[T]) modelColumns() clause.Expr {
+	return clause.Expr{SQL: "?.*", Vars: []any{clause.Table{Name: builder.table()}}}
+}
+
+// addSelect
+// Adds an expression to the selected columns. Columns selected with
+// Select() are kept, and if none were, all columns of the model are
+func (builder *QueryBuilder[T]) addSelect(expression clause.Expr) {
+	if len(builder.selects) == 0 {
+		columns := builder.query.Statement.Selects
+
+		if len(columns) == 0 {
+			builder.selects = append(builder.selects, builder.modelColumns())
+		}
+
+		modelSchema := parseSchema(builder.query, builder.model)
+
+		for _, column := range columns {
+			selected := clause.Column{Name: column, Raw: true}
+
+			if field := modelSchema.LookUpField(column); field != nil {
+				selected = clause.Column{Name: field.DBName}
+			}
+
+			builder.selects = append(builder.selects, clause.Expr{SQL: "?", Vars: []any{selected}})
+		}
+	}
+
+	builder.selects = append(builder.selects, expression)
+	builder.applySelects()
+}
+
+// applySelects
+// Applies the expressions added to the selected columns
+func (builder *QueryBuilder[T]) applySelects() {
+	sql := make([]string, 0, len(builder.selects))
+	var vars []any
+
+	for _, expression := range builder.selects {
+		sql = append(sql, expression.SQL)
+		vars = append(vars, expression.Vars...)
+	}
+
+	builder.query = builder.query.Clauses(clause.Select{
+		Distinct:   builder.query.Statement.Distinct,
+		Expression: clause.Expr{SQL: strings.Join(sql, ", "), Vars: vars},
+	})
+
+	builder.query.Statement.Selects = nil
+}
+
 // Exists
 // Checks if the query find any results
 func (builder *QueryBuilder[T]) Exists() bool {
+	defer builder.observe("Exists")(nil)
+
 	var entries []T
 	var result *gorm.DB
 
-	if result = builder.query.First(&entries); result.Error != nil {
+	if result = builder.compiled().First(&entries); result.Error != nil {
 		if result.Error.Error() == "record not found" {
 			return false
 		}
@@ -70,21 +210,45 @@ func (builder *QueryBuilder[T]) Exists() bool {
 // Get
 // Executes the query and get a collection containing all results
 func (builder *QueryBuilder[T]) Get() *Collection.Collection[T] {
+	defer builder.observe("Get")(nil)
+
+	builder.applyRelationships("Get")
+
 	var entries []T
 
-	builder.applyRelationships()
+	if builder.shouldRemember() {
+		key := cacheKey(builder.compiled(), func(dryRun *gorm.DB) *gorm.DB {
+			return dryRun.Find(&[]T{})
+		})
 
-	if result := builder.query.Find(&entries); result.Error != nil {
-		panic("QueryBuilder[Get]: " + result.Error.Error())
+		entries = remember(builder.cache, builder.remember, key, builder.cacheTag(), builder.get)
+	} else {
+		entries = builder.get()
 	}
 
+	builder.trackEntries(entries)
+
 	return Collection.Collect(entries)
 }
 
+// get
+// Executes the query for all results
+func (builder *QueryBuilder[T]) get() []T {
+	var entries []T
+
+	if result := builder.compiled().Find(&entries); result.Error != nil {
+		panic("QueryBuilder[Get]: " + result.Error.Error())
+	}
+
+	return entries
+}
+
 // Paginate
 // Executes the query and get a paginates results
 func (builder *QueryBuilder[T]) Paginate(page int, perPage int, path string) *Paginator.Paginator[T] {
-	return Paginator.Paginate[T](builder.query, &Paginator.Boundaries{
+	defer builder.observe("Paginate")(nil)
+
+	return Paginator.Paginate[T](builder.compiled(), &Paginator.Boundaries{
 		Page:    page,
 		PerPage: perPage,
 		Path:    path,
@@ -94,11 +258,39 @@ func (builder *QueryBuilder[T]) Paginate(page int, perPage int, path string) *Pa
 // First
 // Executes the query and fetches the first result
 func (builder *QueryBuilder[T]) First() *T {
-	var entry T
+	defer builder.observe("First")(nil)
+
+	return builder.findFirst()
+}
+
+// findFirst
+// Fetches the first result, from the cache if asked to remember results
+func (builder *QueryBuilder[T]) findFirst() *T {
+	builder.applyRelationships("First")
+
+	var entry *T
 
-	builder.applyRelationships()
+	if builder.shouldRemember() {
+		key := cacheKey(builder.compiled(), func(dryRun *gorm.DB) *gorm.DB {
+			return dryRun.First(new(T))
+		})
 
-	result := builder.query.First(&entry)
+		entry = remember(builder.cache, builder.remember, key, builder.cacheTag(), builder.first)
+	} else {
+		entry = builder.first()
+	}
+
+	builder.trackSnapshots(entry)
+
+	return entry
+}
+
+// first
+// Executes the query for the first result
+func (builder *QueryBuilder[T]) first() *T {
+	var entry T
+
+	result := builder.compiled().First(&entry)
 
 	if result.Error != nil {
 		if result.Error.Error() == "record not found" {
@@ -118,7 +310,9 @@ func (builder *QueryBuilder[T]) First() *T {
 // FirstOrFail
 // Executes the query and fetches the first result or dies trying
 func (builder *QueryBuilder[T]) FirstOrFail() *T {
-	entry := builder.First()
+	defer builder.observe("FirstOrFail")(nil)
+
+	entry := builder.findFirst()
 
 	if entry == nil {
 		panic("QueryBuilder[FirstOrFail]: Model not found!")
@@ -127,26 +321,73 @@ func (builder *QueryBuilder[T]) FirstOrFail() *T {
 	return entry
 }
 
+// Pluck
+// Executes the query and gets a slice of the values of a single column
+func Pluck[V any, T any](builder *QueryBuilder[T], column string) []V {
+	defer builder.observe("Pluck")(nil)
+
+	return pluck[V](builder, column)
+}
+
+// pluck
+// Executes the query for the values of a single column
+func pluck[V any, T any](builder *QueryBuilder[T], column string) []V {
+	var values []V
+
+	if result := builder.compiled().Model(builder.model).Pluck(column, &values); result.Error != nil {
+		panic("QueryBuilder[Pluck]: " + result.Error.Error())
+	}
+
+	return values
+}
+
+// Value
+// Executes the query and gets the value of a single column of the
+// first result, or the zero value if there are no results
+func Value[V any, T any](builder *QueryBuilder[T], column string) V {
+	defer builder.observe("Value")(nil)
+
+	var value V
+
+	values := pluck[V](builder.Take(1), column)
+
+	if len(values) > 0 {
+		value = values[0]
+	}
+
+	return value
+}
+
 // Delete
 // Performs a delete query
 func (builder *QueryBuilder[T]) Delete() bool {
+	defer builder.observe("Delete")(nil)
+
 	var model T
 
+	if len(builder.unions) > 0 {
+		panic("QueryBuilder[Delete]: Unions cannot be deleted")
+	}
+
+	builder.UseWriter()
+
 	if result := builder.query.Delete(&model); result.Error != nil {
 		panic("QueryBuilder[Delete]: " + result.Error.Error())
 	}
 
+	builder.invalidateCache()
+
 	return true
 }
 
 // applyRelationships
 // Applies any relationships set with the With() function on models
-func (builder *QueryBuilder[T]) applyRelationships() {
+func (builder *QueryBuilder[T]) applyRelationships(method string) {
 	if Support.Implements[WithRelationships](builder.model) {
 		relationships := Support.Cast[WithRelationships](builder.model).With()
 
 		for _, relationship := range relationships {
-			builder.query = builder.query.Preload(relationship)
+			builder.preload(method, relationship, nil)
 		}
 	}
 }
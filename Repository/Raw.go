@@ -0,0 +1,207 @@
+package Repository
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/nbj/go-collections/Collection"
+	"gorm.io/gorm/clause"
+	"regexp"
+	"strings"
+)
+
+// namedBinding
+// Matches named bindings like @name in raw SQL
+var namedBinding = regexp.MustCompile(`@([A-Za-z_][A-Za-z0-9_]*)`)
+
+// rawOrderPrefix
+// Marks orderings added with OrderByRaw among the tracked orderings
+const rawOrderPrefix = "\x00raw:"
+
+// Raw
+// Executes raw SQL and returns the rows as a collection of entries, with
+// relationships of the model loaded. Bindings are either positional
+// using ?, or named using @name with a map[string]any or sql.Named
+// arguments. Raw SQL is not scoped to the tenant of the repository
+func (repository *Repository[T]) Raw(sql string, bindings ...any) *Collection.Collection[T] {
+	repository, finish := repository.observe("Raw")
+	defer finish(nil)
+
+	var entries []T
+
+	expression := rawExpression("Raw", sql, bindings)
+	query := repository.applyRelationships("Raw", repository.reader()).Raw(expression.SQL, expression.Vars...)
+
+	if result := query.Find(&entries); result.Error != nil {
+		repository.latestError = result.Error
+		panic("Repository[Raw]: " + result.Error.Error())
+	}
+
+	repository.trackEntries(entries)
+
+	return Collection.Collect(entries)
+}
+
+// Exec
+// Executes a raw SQL statement on the primary connection and returns the
+// number of rows affected. Bindings work as they do for Raw. As the rows
+// written are unknown, all cached results of the model are invalidated
+func (repository *Repository[T]) Exec(sql string, bindings ...any) (affected int64, err error) {
+	repository, finish := repository.observe("Exec")
+	defer finish(&err)
+
+	expression := rawExpression("Exec", sql, bindings)
+	result := repository.connection.Exec(expression.SQL, expression.Vars...)
+
+	if result.Error != nil {
+		repository.latestError = result.Error
+
+		return 0, result.Error
+	}
+
+	repository.invalidateCache()
+
+	return result.RowsAffected, nil
+}
+
+// WhereRaw
+// Filters rows using raw SQL, with positional or named bindings
+func (builder *QueryBuilder[T]) WhereRaw(sql string, bindings ...any) *QueryBuilder[T] {
+	builder.query = builder.query.Where(rawExpression("WhereRaw", sql, bindings))
+
+	return builder
+}
+
+// OrderByRaw
+// Orders the results using raw SQL, with positional or named bindings
+func (builder *QueryBuilder[T]) OrderByRaw(sql string, bindings ...any) *QueryBuilder[T] {
+	builder.rawOrders = append(builder.rawOrders, rawExpression("OrderByRaw", sql, bindings))
+
+	return builder.addOrder(clause.OrderByColumn{
+		Column: clause.Column{Name: fmt.Sprintf("%s%d", rawOrderPrefix, len(builder.rawOrders)-1), Raw: true},
+	})
+}
+
+// SelectRaw
+// Adds raw SQL to the selected columns, with positional or named bindings
+func (builder *QueryBuilder[T]) SelectRaw(sql string, bindings ...any) *QueryBuilder[T] {
+	builder.addSelect(rawExpression("SelectRaw", sql, bindings))
+
+	return builder
+}
+
+// HavingRaw
+// Filters grouped rows using raw SQL, with positional or named bindings
+func (builder *QueryBuilder[T]) HavingRaw(sql string, bindings ...any) *QueryBuilder[T] {
+	builder.query = builder.query.Having(rawExpression("HavingRaw", sql, bindings))
+
+	return builder
+}
+
+// rawExpression
+// Turns raw SQL and its bindings into an expression. Named bindings
+// are rewritten to positional ones, so raw expressions can be
+// combined with other expressions of the same clause
+func rawExpression(method string, sql string, bindings []any) clause.Expr {
+	named, isNamed := namedBindings(bindings)
+
+	if !isNamed {
+		return clause.Expr{SQL: sql, Vars: bindings}
+	}
+
+	var vars []any
+	var missing string
+
+	positional := namedBinding.ReplaceAllStringFunc(sql, func(match string) string {
+		value, exists := named[match[1:]]
+
+		if !exists {
+			missing = match
+
+			return match
+		}
+
+		vars = append(vars, value)
+
+		return "?"
+	})
+
+	if missing != "" {
+		panic("QueryBuilder[" + method + "]: No binding for " + missing)
+	}
+
+	return clause.Expr{SQL: positional, Vars: vars}
+}
+
+// namedBindings
+// Collects named bindings passed as a map or as sql.Named arguments
+func namedBindings(bindings []any) (map[string]any, bool) {
+	if len(bindings) == 1 {
+		if named, isMap := bindings[0].(map[string]any); isMap {
+			return named, true
+		}
+	}
+
+	named := map[string]any{}
+
+	for _, binding := range bindings {
+		argument, isNamed := binding.(sql.NamedArg)
+
+		if !isNamed {
+			return nil, false
+		}
+
+		named[argument.Name] = argument.Value
+	}
+
+	return named, len(named) > 0
+}
+
+// buildOrders
+// Builds the ORDER BY clause, writing orderings added with
+// OrderByRaw as the raw expressions they stand in for
+func buildOrders(rawOrders []clause.Expr) clause.ClauseBuilder {
+	return func(orderClause clause.Clause, builder clause.Builder) {
+		orderBy, isOrderBy := orderClause.Expression.(clause.OrderBy)
+
+		if !isOrderBy || orderBy.Expression != nil {
+			orderClause.Builder = nil
+			orderClause.Build(builder)
+
+			return
+		}
+
+		builder.WriteString("ORDER BY ")
+
+		for index, column := range orderBy.Columns {
+			if index > 0 {
+				builder.WriteByte(',')
+			}
+
+			if position, isRaw := rawOrderPosition(column.Column); isRaw && position < len(rawOrders) {
+				rawOrders[position].Build(builder)
+			} else {
+				builder.WriteQuoted(column.Column)
+			}
+
+			if column.Desc {
+				builder.WriteString(" DESC")
+			}
+		}
+	}
+}
+
+// rawOrderPosition
+// Finds the position of the raw expression a column stands in for
+func rawOrderPosition(column clause.Column) (int, bool) {
+	if !column.Raw || !strings.HasPrefix(column.Name, rawOrderPrefix) {
+		return 0, false
+	}
+
+	var position int
+
+	if _, err := fmt.Sscanf(strings.TrimPrefix(column.Name, rawOrderPrefix), "%d", &position); err != nil {
+		return 0, false
+	}
+
+	return position, true
+}
@@ -0,0 +1,69 @@
+package Repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"sync/atomic"
+)
+
+// ReadPolicy
+// Picks the connection a read is performed on among the replicas
+type ReadPolicy func(readers []*gorm.DB) *gorm.DB
+
+var defaultReadPolicy = RoundRobin()
+
+// RoundRobin
+// Creates a read policy spreading reads evenly across replicas
+func RoundRobin() ReadPolicy {
+	var counter atomic.Uint64
+
+	return func(readers []*gorm.DB) *gorm.DB {
+		return readers[(counter.Add(1)-1)%uint64(len(readers))]
+	}
+}
+
+// UseWriter
+// Pins all reads of the repository to the primary connection,
+// making sure entries just written are read back
+func (repository *Repository[T]) UseWriter() *Repository[T] {
+	repository.useWriter = true
+
+	return repository
+}
+
+// reader
+// Gets the connection to perform reads on
+func (repository *Repository[T]) reader() *gorm.DB {
+	if repository.useWriter || len(repository.readers) == 0 {
+		return repository.connection
+	}
+
+	if repository.readPolicy != nil {
+		return repository.readPolicy(repository.readers)
+	}
+
+	return defaultReadPolicy(repository.readers)
+}
+
+// UseWriter
+// Pins the query to the primary connection, making sure
+// entries just written are read back
+func (builder *QueryBuilder[T]) UseWriter() *QueryBuilder[T] {
+	if builder.writer == nil || builder.query.Statement.ConnPool == builder.writer.Statement.ConnPool {
+		return builder
+	}
+
+	queryContext := builder.query.Statement.Context
+
+	if queryContext == nil {
+		queryContext = context.Background()
+	}
+
+	// The session clones the statement, so the conditions added so
+	// far are kept while the connection pool is swapped out
+	builder.query = builder.query.Session(&gorm.Session{Context: queryContext})
+	builder.query.Statement.ConnPool = builder.writer.Statement.ConnPool
+	builder.query.Config.ConnPool = builder.writer.Config.ConnPool
+
+	return builder
+}
@@ -6,53 +6,133 @@ import (
 	"github.com/nbj/go-collections/Collection"
 	"github.com/nbj/go-support/Support"
 	"gorm.io/gorm"
-	"log"
+	"gorm.io/gorm/schema"
+	"log/slog"
+	"sync"
+	"time"
 )
 
-var defaultConfiguration *Config
+var schemaCache sync.Map
 
 type WithRelationships interface {
 	With() []string
 }
 
 type Repository[T any] struct {
-	connection  *gorm.DB
+	connection *gorm.DB
+	readers    []*gorm.DB
+	readPolicy ReadPolicy
+	useWriter  bool
+
+	tenancy tenancy
+
+	cache         Cache
+	cacheTTL      time.Duration
+	invalidations *invalidations
+
+	snapshots       *snapshots
+	timezone        *time.Location
+	queryLogger     *queryLogger
+	instrumentation Instrumentation
+
 	model       *T
 	query       *gorm.DB
 	latestError error
 }
 
 type Config struct {
+	// The primary connection. All writes go here
 	DatabaseConnection *gorm.DB
+
+	// Optional replicas. Reads are spread across these
+	ReadConnections []*gorm.DB
+
+	// Picks the replica used for a read. Defaults to round-robin
+	ReadPolicy ReadPolicy
+
+	// Optional tenant repositories are scoped to
+	Tenant any
+
+	// How entries of tenants are kept apart. Defaults to a tenant column
+	TenancyStrategy TenancyStrategy
+
+	// Optional cache for query results. Writes through repositories
+	// invalidate all cached results of the model written
+	Cache Cache
+
+	// How long First() results are cached. Zero disables caching
+	// unless asked for with Remember() on the query builder
+	CacheTTL time.Duration
+
+	// The timezone dates are interpreted in by date helpers
+	// like WhereDate and WhereMonth. Defaults to UTC. On SQLite,
+	// WhereMonth and WhereTime only support fixed offsets
+	Timezone *time.Location
+
+	// Optional logger all queries are logged to, with their
+	// duration, rows affected, calling method and model
+	Logger *slog.Logger
+
+	// Queries taking at least this long are logged as warnings,
+	// using the default logger when no logger is configured
+	SlowQueryThreshold time.Duration
+
+	// Columns whose bindings are redacted in query logs, in addition
+	// to fields of models tagged with `repository:"redact"`
+	RedactedColumns []string
+
+	// Optional instrumentation observing terminal calls of repositories
+	// and query builders, and transactions. Defaults to no-op
+	Instrumentation Instrumentation
+
+	// Tags written inside a transaction
+	invalidations *invalidations
 }
 
 // Of
-// Named constructor for creating instances of a repository
+// Named constructor for creating instances of a repository.
+// Panics if no configuration can be resolved for the model
 func Of[T any](config ...Config) *Repository[T] {
-	// Check if it is impossible to instantiate a repository instance and bail
-	if len(config) == 0 && defaultConfiguration == nil {
-		log.Fatal("No configuration passed to Repository and no default configuration available.")
+	repository, err := Make[T](config...)
+
+	if err != nil {
+		panic("Repository[Of]: " + err.Error())
 	}
 
+	return repository
+}
+
+// Make
+// Named constructor for creating instances of a repository.
+// Returns an error if no configuration can be resolved for the model
+func Make[T any](config ...Config) (*Repository[T], error) {
 	// Create the repository instance
 	var repository Repository[T]
 	repository.model = new(T)
 
-	// Assign the appropriate configuration
-	repository.applyConfiguration(defaultConfiguration)
+	// Resolve and assign the appropriate configuration
+	resolved, err := resolveConfiguration(repository.model, config)
 
-	if len(config) > 0 {
-		repository.applyConfiguration(&config[0])
+	if err != nil {
+		return nil, err
 	}
 
+	repository.applyConfiguration(resolved)
+
 	// Return the newly created repository
-	return &repository
+	return &repository, nil
 }
 
 // SetDefaultConfig
 // Sets the default configuration repositories will be instantiated with
 func SetDefaultConfig(config *Config) {
-	defaultConfiguration = config
+	if config == nil {
+		RemoveConnection(DefaultConnection)
+
+		return
+	}
+
+	AddConnection(DefaultConnection, *config)
 }
 
 // GetLatestError
@@ -64,17 +144,56 @@ func (repository *Repository[T]) GetLatestError() error {
 // applyConfiguration
 // Assigns a configuration to the repository instance
 func (repository *Repository[T]) applyConfiguration(config *Config) {
-	repository.connection = config.DatabaseConnection
+	repository.queryLogger = newQueryLogger(config)
+	repository.connection = repository.queryLogger.instrument(config.DatabaseConnection)
+	repository.readers = make([]*gorm.DB, 0, len(config.ReadConnections))
+
+	for _, reader := range config.ReadConnections {
+		repository.readers = append(repository.readers, repository.queryLogger.instrument(reader))
+	}
+
+	repository.readPolicy = config.ReadPolicy
+	repository.tenancy = tenancy{tenant: config.Tenant, strategy: config.TenancyStrategy}
+	repository.cache = config.Cache
+	repository.cacheTTL = config.CacheTTL
+	repository.invalidations = config.invalidations
+	repository.timezone = config.Timezone
+	repository.instrumentation = instrumentationOf(config)
+}
+
+// schema
+// Parses the gorm schema of the model handled by the repository
+func (repository *Repository[T]) schema() *schema.Schema {
+	return parseSchema(repository.connection, repository.model)
+}
+
+// parseSchema
+// Parses the gorm schema of a model using the naming strategy of the connection
+func parseSchema(connection *gorm.DB, model any) *schema.Schema {
+	modelSchema, err := schema.Parse(model, &schemaCache, connection.NamingStrategy)
+
+	if err != nil {
+		panic("Repository[Schema]: " + err.Error())
+	}
+
+	return modelSchema
 }
 
 // applyRelationships
-// Applies any relationships set with the With() function on models
-func (repository *Repository[T]) applyRelationships(query *gorm.DB) *gorm.DB {
+// Applies any relationships set with the With() function on models,
+// scoped to the tenant of the repository
+func (repository *Repository[T]) applyRelationships(method string, query *gorm.DB) *gorm.DB {
 	if Support.Implements[WithRelationships](repository.model) {
 		relations := Support.Cast[WithRelationships](repository.model).With()
+		modelSchema := repository.schema()
 
 		for _, relation := range relations {
-			query = query.Preload(relation)
+			var err error
+
+			if query, err = repository.tenancy.preload(query, modelSchema, relation, nil); err != nil {
+				repository.latestError = err
+				panic("Repository[" + method + "]: " + err.Error())
+			}
 		}
 	}
 
@@ -86,7 +205,15 @@ func (repository *Repository[T]) applyRelationships(query *gorm.DB) *gorm.DB {
 func (repository *Repository[T]) Query() *QueryBuilder[T] {
 	var builder QueryBuilder[T]
 
-	builder.query = repository.connection
+	builder.query = repository.scoped("Query", repository.reader())
+	builder.writer = repository.connection
+	builder.model = repository.model
+	builder.cache = repository.cache
+	builder.invalidations = repository.invalidations
+	builder.snapshots = repository.snapshots
+	builder.timezone = repository.timezone
+	builder.instrumentation = repository.instrumentation
+	builder.tenancy = repository.tenancy
 
 	return &builder
 }
@@ -95,38 +222,84 @@ func (repository *Repository[T]) Query() *QueryBuilder[T] {
 // Gets a collection of all entries in the repository.
 // Returns nil if query fails
 func (repository *Repository[T]) All() *Collection.Collection[T] {
+	repository, finish := repository.observe("All")
+	defer finish(nil)
+
 	var entries []T
 
-	query := repository.connection
-	query = repository.applyRelationships(query)
+	query := repository.scoped("All", repository.reader())
+	query = repository.applyRelationships("All", query)
 
 	if result := query.Find(&entries); result.Error != nil {
 		repository.latestError = result.Error
 		panic("Repository[All]: " + result.Error.Error())
 	}
 
+	repository.trackEntries(entries)
+
 	return Collection.Collect(entries)
 }
 
 // Create
 // Creates a new database entry
 func (repository *Repository[T]) Create(value T) *T {
-	if result := repository.connection.Create(&value); result.Error != nil {
+	repository, finish := repository.observe("Create")
+	defer finish(nil)
+
+	repository.initializeVersion(&value)
+
+	if err := repository.assignTenant(&value); err != nil {
+		repository.latestError = err
+		panic("Repository[Create]: " + err.Error())
+	}
+
+	query := repository.scoped("Create", repository.connection)
+
+	if result := query.Create(&value); result.Error != nil {
 		repository.latestError = result.Error
 		panic("Repository[Create]: " + result.Error.Error())
 	}
 
+	repository.invalidateCache()
+
 	return &value
 }
 
 // Update
 // Updates an existing database entry with values from map.
 // Returns true if successful, false if not
-func (repository *Repository[T]) Update(id uuid.UUID, values any) error {
-	query := repository.connection.
-		Model(repository.model).
-		Where("id = ?", id).
-		Updates(values)
+func (repository *Repository[T]) Update(id uuid.UUID, values any) (err error) {
+	repository, finish := repository.observe("Update")
+	defer finish(&err)
+
+	query, err := repository.scopeTenant(repository.connection.Model(repository.model))
+
+	if err != nil {
+		repository.latestError = err
+
+		return repository.latestError
+	}
+
+	query = query.Where("id = ?", id)
+
+	// Versioned models are only updated if the version passed
+	// along still matches the version stored in the database
+	versionField := repository.versionField()
+
+	if versionField != nil {
+		var version int64
+		var ok bool
+
+		if values, version, ok = extractVersion(versionField, values); !ok {
+			repository.latestError = ErrMissingVersion
+
+			return repository.latestError
+		}
+
+		query = query.Where(versionField.DBName+" = ?", version)
+	}
+
+	query = query.Updates(values)
 
 	if query.Error != nil {
 		repository.latestError = query.Error
@@ -134,56 +307,98 @@ func (repository *Repository[T]) Update(id uuid.UUID, values any) error {
 		return repository.latestError
 	}
 
+	if query.RowsAffected == 0 && versionField != nil && repository.exists(id) {
+		repository.latestError = ErrStaleModel
+
+		return repository.latestError
+	}
+
 	if query.RowsAffected == 0 {
 		repository.latestError = errors.New("Repository[Update]: No rows were affected")
 
 		return repository.latestError
 	}
 
+	repository.invalidateCache()
+
 	return nil
 }
 
+// exists
+// Checks if an entry with a specific id exists
+func (repository *Repository[T]) exists(id uuid.UUID) bool {
+	var count int64
+
+	repository.scoped("Update", repository.connection.Model(repository.model)).
+		Where("id = ?", id).
+		Count(&count)
+
+	return count > 0
+}
+
 // GormQuery
 // Takes a closure containing a gorm query, executes it and
 // returns the result as a collection of entries. Returns nil if
 // query fails
 func (repository *Repository[T]) GormQuery(closure func(query *gorm.DB) *gorm.DB) *Collection.Collection[T] {
+	repository, finish := repository.observe("GormQuery")
+	defer finish(nil)
+
 	var entries []T
 
-	query := closure(repository.connection)
-	query = repository.applyRelationships(query)
+	query := closure(repository.scoped("GormQuery", repository.reader()))
+	query = repository.applyRelationships("GormQuery", query)
 
 	if result := query.Find(&entries); result.Error != nil {
 		repository.latestError = result.Error
 		panic("Repository[GormQuery]: " + result.Error.Error())
 	}
 
+	repository.trackEntries(entries)
+
 	return Collection.Collect(entries)
 }
 
 // First
-// Gets the first database entry that matches the queries passed
+// Gets the first database entry that matches the queries passed.
+// Results are cached if the repository is configured to do so
 func (repository *Repository[T]) First(closures ...func(query *gorm.DB) *gorm.DB) *T {
-	var entry T
-
-	query := repository.connection
-	query = repository.applyRelationships(query)
+	repository, finish := repository.observe("First")
+	defer finish(nil)
 
-	// If no closures are passed to the method
-	if len(closures) == 0 {
-		if result := query.First(&entry); result.Error != nil {
-			repository.latestError = result.Error
-			panic("Repository[First]: " + result.Error.Error())
-		}
-
-		return &entry
-	}
+	query := repository.scoped("First", repository.reader())
+	query = repository.applyRelationships("First", query)
 
 	// Apply all closures to the query
 	for _, closure := range closures {
 		query = closure(query)
 	}
 
+	if !repository.shouldRemember(query) {
+		entry := repository.first(query)
+		repository.trackSnapshots(entry)
+
+		return entry
+	}
+
+	key := cacheKey(query, func(dryRun *gorm.DB) *gorm.DB {
+		return dryRun.First(new(T))
+	})
+
+	entry := remember(repository.cache, repository.cacheTTL, key, repository.schema().Table, func() *T {
+		return repository.first(query)
+	})
+
+	repository.trackSnapshots(entry)
+
+	return entry
+}
+
+// first
+// Executes a query for the first database entry
+func (repository *Repository[T]) first(query *gorm.DB) *T {
+	var entry T
+
 	if result := query.First(&entry); result.Error != nil {
 		repository.latestError = result.Error
 		panic("Repository[First]: " + result.Error.Error())
@@ -193,15 +408,41 @@ func (repository *Repository[T]) First(closures ...func(query *gorm.DB) *gorm.DB
 }
 
 // Transaction
-// Performs a closure as a database transaction
-func Transaction(closure func(transactionConfig Config) error) error {
+// Performs a closure as a database transaction. The transaction is
+// started on the default configuration unless one is passed. All
+// queries inside the transaction go to the primary connection
+func Transaction(closure func(transactionConfig Config) error, config ...Config) (err error) {
+	resolved, err := resolveConfiguration(nil, config)
+
+	if err != nil {
+		return err
+	}
+
+	// The transaction runs in the context of its operation, so
+	// operations inside the closure are observed as part of it
+	ctx, finish := startOperation(instrumentationOf(resolved), resolved.DatabaseConnection.Statement.Context, "Transaction", nil)
+
+	defer func() {
+		finishOperation(finish, &err, recover())
+	}()
+
 	// We start by creating the transaction
-	transaction := defaultConfiguration.DatabaseConnection.Begin()
+	transaction := resolved.DatabaseConnection.WithContext(ctx).Begin()
 
 	// Create a transaction config to use for repositories inside
 	// the closure housing the transaction
 	transactionConfig := Config{
 		DatabaseConnection: transaction,
+		Tenant:             resolved.Tenant,
+		TenancyStrategy:    resolved.TenancyStrategy,
+		Cache:              resolved.Cache,
+		CacheTTL:           resolved.CacheTTL,
+		Timezone:           resolved.Timezone,
+		Logger:             resolved.Logger,
+		SlowQueryThreshold: resolved.SlowQueryThreshold,
+		RedactedColumns:    resolved.RedactedColumns,
+		Instrumentation:    resolved.Instrumentation,
+		invalidations:      &invalidations{},
 	}
 
 	// Pass config to closure and execute query. If any errors
@@ -212,8 +453,10 @@ func Transaction(closure func(transactionConfig Config) error) error {
 		return err
 	}
 
-	// If everything went well we commit the transaction
+	// If everything went well we commit the transaction and
+	// invalidate results cached while it was in progress
 	transaction.Commit()
+	transactionConfig.invalidations.flush(resolved.Cache)
 
 	return nil
 }
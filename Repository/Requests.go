@@ -0,0 +1,290 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nbj/go-paginator/Paginator"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRequest
+// Returned by FromRequest when the query string asks for filters,
+// sorts, includes or pages that are not allowed
+var ErrInvalidRequest = errors.New("QueryBuilder[FromRequest]: Invalid request")
+
+// filterParameter
+// Matches filter[name] and filter[name][from|to] query parameters
+var filterParameter = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[(from|to)\])?$`)
+
+// likeEscaper
+// Escapes the wildcards of LIKE patterns, using ! as the escape
+// character as it needs no escaping in any SQL dialect
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+type FilterType int
+
+const (
+	FilterExact FilterType = iota
+	FilterPartial
+	FilterRange
+	FilterScope
+)
+
+type Filter struct {
+	Type   FilterType
+	Column string
+	Scope  func(query *gorm.DB, values []string) *gorm.DB
+}
+
+type AllowList struct {
+	Filters     map[string]Filter
+	Sorts       []string
+	Includes    []string
+	DefaultSort string
+	MaxPerPage  int
+}
+
+// ExactFilter
+// Filters on a column being equal to one of the comma separated values
+func ExactFilter(column string) Filter {
+	return Filter{Type: FilterExact, Column: column}
+}
+
+// PartialFilter
+// Filters on a column containing one of the comma separated values
+func PartialFilter(column string) Filter {
+	return Filter{Type: FilterPartial, Column: column}
+}
+
+// RangeFilter
+// Filters on a column being within filter[name][from] and filter[name][to]
+func RangeFilter(column string) Filter {
+	return Filter{Type: FilterRange, Column: column}
+}
+
+// ScopeFilter
+// Filters by applying a scope to the query, passing it the comma separated values
+func ScopeFilter(scope func(query *gorm.DB, values []string) *gorm.DB) Filter {
+	return Filter{Type: FilterScope, Scope: scope}
+}
+
+// FromRequest
+// Applies the filters, sorts, includes and page of a query string to the
+// query. Only what is in the allow list is accepted, anything else results
+// in an ErrInvalidRequest. Sorts are comma separated columns, prefixed with
+// - to sort descending, and includes are comma separated relationships
+func (builder *QueryBuilder[T]) FromRequest(values url.Values, allowList AllowList) (*QueryBuilder[T], error) {
+	if err := builder.applyRequestFilters(values, allowList); err != nil {
+		return builder, err
+	}
+
+	if err := builder.applyRequestSorts(values, allowList); err != nil {
+		return builder, err
+	}
+
+	if err := builder.applyRequestIncludes(values, allowList); err != nil {
+		return builder, err
+	}
+
+	if err := builder.applyRequestPage(values, allowList); err != nil {
+		return builder, err
+	}
+
+	return builder, nil
+}
+
+// PaginateRequest
+// Executes the query and gets the page requested by the query string passed to FromRequest
+func (builder *QueryBuilder[T]) PaginateRequest(path string) *Paginator.Paginator[T] {
+	return builder.Paginate(builder.page, builder.perPage, path)
+}
+
+// applyRequestFilters
+// Applies the filter[name] parameters of the request
+func (builder *QueryBuilder[T]) applyRequestFilters(values url.Values, allowList AllowList) error {
+	ranges := map[string]map[string]string{}
+	parameters := make([]string, 0, len(values))
+
+	for parameter := range values {
+		parameters = append(parameters, parameter)
+	}
+
+	sort.Strings(parameters)
+
+	for _, parameter := range parameters {
+		if !strings.HasPrefix(parameter, "filter") {
+			continue
+		}
+
+		match := filterParameter.FindStringSubmatch(parameter)
+
+		if match == nil {
+			return fmt.Errorf("%w: malformed filter \"%s\"", ErrInvalidRequest, parameter)
+		}
+
+		name, bound, value := match[1], match[2], values.Get(parameter)
+		filter, allowed := allowList.Filters[name]
+
+		if !allowed {
+			return fmt.Errorf("%w: filter \"%s\" is not allowed", ErrInvalidRequest, name)
+		}
+
+		if (filter.Type == FilterRange) != (bound != "") {
+			return fmt.Errorf("%w: malformed filter \"%s\"", ErrInvalidRequest, parameter)
+		}
+
+		column := filter.Column
+
+		if column == "" {
+			column = name
+		}
+
+		switch filter.Type {
+		case FilterExact:
+			builder.query = builder.query.Where("? IN ?", builder.resolveColumn("FromRequest", column, false), splitList(value))
+		case FilterPartial:
+			builder.wherePartial(builder.resolveColumn("FromRequest", column, false), splitList(value))
+		case FilterRange:
+			if ranges[column] == nil {
+				ranges[column] = map[string]string{}
+			}
+
+			ranges[column][bound] = value
+		case FilterScope:
+			builder.query = filter.Scope(builder.query, splitList(value))
+		}
+	}
+
+	columns := make([]string, 0, len(ranges))
+
+	for column := range ranges {
+		columns = append(columns, column)
+	}
+
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		resolved := builder.resolveColumn("FromRequest", column, false)
+
+		if from, exists := ranges[column]["from"]; exists {
+			builder.query = builder.query.Where("? >= ?", resolved, from)
+		}
+
+		if to, exists := ranges[column]["to"]; exists {
+			builder.query = builder.query.Where("? <= ?", resolved, to)
+		}
+	}
+
+	return nil
+}
+
+// applyRequestSorts
+// Applies the sort parameter of the request, or the default sort when absent
+func (builder *QueryBuilder[T]) applyRequestSorts(values url.Values, allowList AllowList) error {
+	sorts := allowList.DefaultSort
+
+	if values.Has("sort") {
+		sorts = values.Get("sort")
+	}
+
+	for _, column := range splitList(sorts) {
+		direction := "asc"
+
+		if strings.HasPrefix(column, "-") {
+			column, direction = column[1:], "desc"
+		}
+
+		if !slices.Contains(allowList.Sorts, column) {
+			return fmt.Errorf("%w: sort \"%s\" is not allowed", ErrInvalidRequest, column)
+		}
+
+		builder.OrderBy(column, direction)
+	}
+
+	return nil
+}
+
+// applyRequestIncludes
+// Applies the include parameter of the request, loading the relationships
+func (builder *QueryBuilder[T]) applyRequestIncludes(values url.Values, allowList AllowList) error {
+	modelSchema := parseSchema(builder.query, builder.model)
+
+	for _, include := range splitList(values.Get("include")) {
+		if !slices.Contains(allowList.Includes, include) {
+			return fmt.Errorf("%w: include \"%s\" is not allowed", ErrInvalidRequest, include)
+		}
+
+		if !hasRelationship(modelSchema, include) {
+			panic("QueryBuilder[FromRequest]: Unknown relationship " + include)
+		}
+
+		builder.With(include)
+	}
+
+	return nil
+}
+
+// applyRequestPage
+// Reads the page and per_page parameters of the request
+func (builder *QueryBuilder[T]) applyRequestPage(values url.Values, allowList AllowList) error {
+	for parameter, target := range map[string]*int{"page": &builder.page, "per_page": &builder.perPage} {
+		if !values.Has(parameter) {
+			continue
+		}
+
+		value, err := strconv.Atoi(values.Get(parameter))
+
+		if err != nil || value < 1 {
+			return fmt.Errorf("%w: %s must be a positive number", ErrInvalidRequest, parameter)
+		}
+
+		*target = value
+	}
+
+	// Without a per_page the maximum is used as well, as the
+	// paginator would otherwise fall back to its own default
+	if allowList.MaxPerPage > 0 && (builder.perPage == 0 || builder.perPage > allowList.MaxPerPage) {
+		builder.perPage = allowList.MaxPerPage
+	}
+
+	return nil
+}
+
+// wherePartial
+// Filters rows by a column containing any of the values
+func (builder *QueryBuilder[T]) wherePartial(column clause.Column, values []string) {
+	conditions := make([]clause.Expression, 0, len(values))
+
+	for _, value := range values {
+		conditions = append(conditions, clause.Expr{
+			SQL:  "? LIKE ? ESCAPE '!'",
+			Vars: []any{column, "%" + likeEscaper.Replace(value) + "%"},
+		})
+	}
+
+	builder.query = builder.query.Where(clause.Or(conditions...))
+}
+
+// hasRelationship
+// Determines if a relationship, possibly nested using dots, exists on a schema
+func hasRelationship(modelSchema *schema.Schema, path string) bool {
+	for _, name := range strings.Split(path, ".") {
+		relationship, exists := modelSchema.Relationships.Relations[name]
+
+		if !exists {
+			return false
+		}
+
+		modelSchema = relationship.FieldSchema
+	}
+
+	return true
+}
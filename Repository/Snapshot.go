@@ -0,0 +1,109 @@
+package Repository
+
+import (
+	"context"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"time"
+)
+
+// takeSnapshot
+// Copies the column values of a model, keyed by field name
+func takeSnapshot(modelSchema *schema.Schema, model reflect.Value) map[string]any {
+	snapshot := make(map[string]any, len(modelSchema.Fields))
+
+	for _, field := range trackedFields(modelSchema) {
+		value, _ := field.ValueOf(context.Background(), model)
+		snapshot[field.Name] = copyValue(value)
+	}
+
+	return snapshot
+}
+
+// diffSnapshot
+// Gets the columns of a model changed since the snapshot was
+// taken, keyed by column name and holding the current values
+func diffSnapshot(modelSchema *schema.Schema, model reflect.Value, snapshot map[string]any) map[string]any {
+	changes := map[string]any{}
+
+	for _, field := range trackedFields(modelSchema) {
+		value, _ := field.ValueOf(context.Background(), model)
+
+		if !sameValue(snapshot[field.Name], value) {
+			changes[field.DBName] = value
+		}
+	}
+
+	return changes
+}
+
+// trackedFields
+// Gets the fields changes are tracked for. Primary keys and
+// timestamps maintained by gorm are left out
+func trackedFields(modelSchema *schema.Schema) []*schema.Field {
+	var fields []*schema.Field
+
+	for _, field := range modelSchema.Fields {
+		if field.DBName == "" || field.PrimaryKey || field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 {
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// copyValue
+// Copies pointers, slices and maps so later changes to the
+// model do not leak into the snapshot
+func copyValue(value any) any {
+	reflection := reflect.ValueOf(value)
+
+	switch reflection.Kind() {
+	case reflect.Pointer:
+		if reflection.IsNil() {
+			return value
+		}
+
+		copied := reflect.New(reflection.Type().Elem())
+		copied.Elem().Set(reflection.Elem())
+
+		return copied.Interface()
+	case reflect.Slice:
+		if reflection.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeSlice(reflection.Type(), reflection.Len(), reflection.Len())
+		reflect.Copy(copied, reflection)
+
+		return copied.Interface()
+	case reflect.Map:
+		if reflection.IsNil() {
+			return value
+		}
+
+		copied := reflect.MakeMapWithSize(reflection.Type(), reflection.Len())
+
+		for _, key := range reflection.MapKeys() {
+			copied.SetMapIndex(key, reflection.MapIndex(key))
+		}
+
+		return copied.Interface()
+	default:
+		return value
+	}
+}
+
+// sameValue
+// Compares a snapshot value to a current value
+func sameValue(before any, after any) bool {
+	if beforeTime, ok := before.(time.Time); ok {
+		if afterTime, ok := after.(time.Time); ok {
+			return beforeTime.Equal(afterTime)
+		}
+	}
+
+	return reflect.DeepEqual(before, after)
+}
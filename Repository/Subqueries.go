@@ -0,0 +1,100 @@
+package Repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// comparisonOperators
+// The operators allowed when comparing columns
+var comparisonOperators = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true,
+}
+
+type Subquery interface {
+	subquery() *gorm.DB
+}
+
+// subquery
+// Gets the query of the builder for use as a subquery. Query
+// builders of any model type can be used as subqueries
+func (builder *QueryBuilder[T]) subquery() *gorm.DB {
+	return builder.compiled().Model(builder.model)
+}
+
+// WhereIn
+// Filters rows whose column is among the values, which can be
+// a slice or a subquery
+func (builder *QueryBuilder[T]) WhereIn(column string, values any) *QueryBuilder[T] {
+	return builder.whereIn("IN", column, values)
+}
+
+// WhereNotIn
+// Filters rows whose column is not among the values, which can
+// be a slice or a subquery
+func (builder *QueryBuilder[T]) WhereNotIn(column string, values any) *QueryBuilder[T] {
+	return builder.whereIn("NOT IN", column, values)
+}
+
+// WhereExists
+// Filters rows for which the subquery has results
+func (builder *QueryBuilder[T]) WhereExists(subquery Subquery) *QueryBuilder[T] {
+	builder.query = builder.query.Where("EXISTS (?)", subquery.subquery())
+
+	return builder
+}
+
+// WhereNotExists
+// Filters rows for which the subquery has no results
+func (builder *QueryBuilder[T]) WhereNotExists(subquery Subquery) *QueryBuilder[T] {
+	builder.query = builder.query.Where("NOT EXISTS (?)", subquery.subquery())
+
+	return builder
+}
+
+// WhereColumn
+// Compares two columns, which is how subqueries are
+// correlated with the query they are part of
+func (builder *QueryBuilder[T]) WhereColumn(first string, operator string, second string) *QueryBuilder[T] {
+	if !comparisonOperators[operator] {
+		panic("QueryBuilder[WhereColumn]: Invalid operator " + operator)
+	}
+
+	builder.query = builder.query.Where("? "+operator+" ?", clause.Column{Name: first}, clause.Column{Name: second})
+
+	return builder
+}
+
+// SelectSub
+// Adds the result of a subquery to the selected columns
+func (builder *QueryBuilder[T]) SelectSub(subquery Subquery, alias string) *QueryBuilder[T] {
+	builder.addSelect(clause.Expr{
+		SQL:  "(?) AS ?",
+		Vars: []any{subquery.subquery(), clause.Column{Name: alias}},
+	})
+
+	return builder
+}
+
+// FromSub
+// Selects from the results of a subquery instead of the table of the model
+func (builder *QueryBuilder[T]) FromSub(subquery Subquery, alias string) *QueryBuilder[T] {
+	builder.from = alias
+	builder.query = builder.query.Table("(?) AS ?", subquery.subquery(), clause.Table{Name: alias})
+
+	return builder
+}
+
+// whereIn
+// Filters rows by comparing a column to a slice or a subquery
+func (builder *QueryBuilder[T]) whereIn(operator string, column string, values any) *QueryBuilder[T] {
+	if subquery, isSubquery := values.(Subquery); isSubquery {
+		builder.query = builder.query.Where("? "+operator+" (?)", clause.Column{Name: column}, subquery.subquery())
+
+		return builder
+	}
+
+	builder.query = builder.query.Where("? "+operator+" ?", clause.Column{Name: column}, values)
+
+	return builder
+}
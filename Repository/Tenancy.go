@@ -0,0 +1,310 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nbj/go-support/Support"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ErrMissingTenant
+// Returned when a tenant scoped model is queried without a tenant
+var ErrMissingTenant = errors.New("Repository: No tenant set for tenant scoped model")
+
+// ErrInvalidTenant
+// Returned when a tenant naming tables or schemas is not a plain identifier
+var ErrInvalidTenant = errors.New("Repository: Invalid tenant")
+
+// tenantIdentifier
+// Matches tenants that can safely be part of a table or schema name
+var tenantIdentifier = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tenantTag
+// The struct tag value marking a field as the tenant column
+const tenantTag = "tenant"
+
+type TenancyStrategy int
+
+const (
+	// TenancyColumn
+	// Tenants share tables. Models with a tenant column are
+	// scoped by a WHERE tenant_id = ? on every query
+	TenancyColumn TenancyStrategy = iota
+
+	// TenancyTablePrefix
+	// Every tenant has its own tables, prefixed by the tenant
+	TenancyTablePrefix
+
+	// TenancySchema
+	// Every tenant has its own schema, named by the tenant
+	TenancySchema
+)
+
+type WithTenant interface {
+	TenantColumn() string
+}
+
+type tenantContextKey struct{}
+
+// TenantContext
+// Carries a tenant identifier in a context
+func TenantContext(ctx context.Context, tenant any) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext
+// Gets the tenant identifier carried in a context, if any
+func TenantFromContext(ctx context.Context) (any, bool) {
+	tenant := ctx.Value(tenantContextKey{})
+
+	return tenant, tenant != nil
+}
+
+// WithContext
+// Performs all queries of the repository using a context. A
+// tenant carried in the context scopes the repository
+func (repository *Repository[T]) WithContext(ctx context.Context) *Repository[T] {
+	ctx = repository.queryLogger.context(ctx)
+	repository.connection = repository.connection.WithContext(ctx)
+
+	readers := make([]*gorm.DB, 0, len(repository.readers))
+
+	for _, reader := range repository.readers {
+		readers = append(readers, reader.WithContext(ctx))
+	}
+
+	repository.readers = readers
+
+	if tenant, ok := TenantFromContext(ctx); ok {
+		repository.tenancy.tenant = tenant
+	}
+
+	return repository
+}
+
+// ForTenant
+// Scopes the repository to a specific tenant
+func (repository *Repository[T]) ForTenant(tenant any) *Repository[T] {
+	repository.tenancy.tenant = tenant
+
+	return repository
+}
+
+// WithoutTenancy
+// Lifts the tenant scope of the repository, giving access
+// to the entries of all tenants
+func (repository *Repository[T]) WithoutTenancy() *Repository[T] {
+	repository.tenancy.disabled = true
+
+	return repository
+}
+
+// tenancy
+// The tenant scope of a repository and the query builders it starts
+type tenancy struct {
+	tenant   any
+	strategy TenancyStrategy
+	disabled bool
+}
+
+// tenantField
+// Finds the schema field holding the tenant, either declared by
+// the WithTenant interface or tagged with `repository:"tenant"`.
+// Returns nil for models not scoped by a tenant column
+func tenantField(modelSchema *schema.Schema, model any) *schema.Field {
+	if Support.Implements[WithTenant](model) {
+		return modelSchema.LookUpField(Support.Cast[WithTenant](model).TenantColumn())
+	}
+
+	for _, field := range modelSchema.Fields {
+		if field.Tag.Get("repository") == tenantTag {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// tenantField
+// Finds the schema field holding the tenant of the model handled by the repository
+func (repository *Repository[T]) tenantField() *schema.Field {
+	return tenantField(repository.schema(), repository.model)
+}
+
+// scope
+// Gets the scope limiting queries of a model to the tenant. Models kept
+// apart by table are read from the table of the tenant, and models with
+// a tenant column are filtered on it. Returns nil if nothing is scoped
+func (scope tenancy) scope(modelSchema *schema.Schema, model any) (func(query *gorm.DB) *gorm.DB, error) {
+	if scope.disabled {
+		return nil, nil
+	}
+
+	if scope.strategy != TenancyColumn {
+		if scope.tenant == nil {
+			return nil, nil
+		}
+
+		table, name, err := scope.table(modelSchema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// The table is always quoted. Other clauses refer to
+		// it by its name, as gorm would set for a plain table.
+		// Inserts name the table explicitly, as some dialects
+		// write inserts into the name rather than the table
+		return func(query *gorm.DB) *gorm.DB {
+			query = query.Table("?", clause.Table{Name: table}).Clauses(clause.Insert{Table: clause.Table{Name: table}})
+			query.Statement.Table = name
+
+			return query
+		}, nil
+	}
+
+	field := tenantField(modelSchema, model)
+
+	if field == nil {
+		return nil, nil
+	}
+
+	if scope.tenant == nil {
+		return nil, ErrMissingTenant
+	}
+
+	return func(query *gorm.DB) *gorm.DB {
+		return query.Where(clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName},
+			Value:  scope.tenant,
+		})
+	}, nil
+}
+
+// relatedScope
+// Gets the scope limiting queries of a related model to the tenant
+func (scope tenancy) relatedScope(relatedSchema *schema.Schema) (func(query *gorm.DB) *gorm.DB, error) {
+	return scope.scope(relatedSchema, reflect.New(relatedSchema.ModelType).Interface())
+}
+
+// preload
+// Preloads a relationship, possibly nested using dots, with every
+// model along the way scoped to the tenant. Arguments are passed
+// on to gorm for the last relationship of the path
+func (scope tenancy) preload(query *gorm.DB, modelSchema *schema.Schema, name string, args []any) (*gorm.DB, error) {
+	names := strings.Split(name, ".")
+
+	for index := range names {
+		relationship, exists := modelSchema.Relationships.Relations[names[index]]
+
+		if !exists {
+			break
+		}
+
+		relatedScope, err := scope.relatedScope(relationship.FieldSchema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if relatedScope != nil {
+			path := strings.Join(names[:index+1], ".")
+
+			if path == name {
+				args = append(args, relatedScope)
+			} else {
+				query = query.Preload(path, append(query.Statement.Preloads[path], relatedScope)...)
+			}
+		}
+
+		modelSchema = relationship.FieldSchema
+	}
+
+	return query.Preload(name, args...), nil
+}
+
+// table
+// Gets the table of a model belonging to the tenant, along with the
+// name it is referred to by. Tenants naming tables or schemas must be
+// plain identifiers, as they often come from requests
+func (scope tenancy) table(modelSchema *schema.Schema) (string, string, error) {
+	tenant := fmt.Sprintf("%v", scope.tenant)
+
+	if !tenantIdentifier.MatchString(tenant) {
+		return "", "", fmt.Errorf("%w \"%s\"", ErrInvalidTenant, tenant)
+	}
+
+	if scope.strategy == TenancySchema {
+		return tenant + "." + modelSchema.Table, modelSchema.Table, nil
+	}
+
+	return tenant + "_" + modelSchema.Table, tenant + "_" + modelSchema.Table, nil
+}
+
+// qualifiedTable
+// Gets the table of a model as written in statements, which is the
+// table of the tenant when tenants are kept apart by table
+func (scope tenancy) qualifiedTable(modelSchema *schema.Schema) (string, error) {
+	if scope.disabled || scope.strategy == TenancyColumn || scope.tenant == nil {
+		return modelSchema.Table, nil
+	}
+
+	table, _, err := scope.table(modelSchema)
+
+	return table, err
+}
+
+// scopeTenant
+// Scopes a query to the tenant of the repository
+func (repository *Repository[T]) scopeTenant(query *gorm.DB) (*gorm.DB, error) {
+	tenantScope, err := repository.tenancy.scope(repository.schema(), repository.model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tenantScope == nil {
+		return query, nil
+	}
+
+	return tenantScope(query), nil
+}
+
+// scoped
+// Scopes a query to the tenant of the repository or dies trying
+func (repository *Repository[T]) scoped(method string, query *gorm.DB) *gorm.DB {
+	scopedQuery, err := repository.scopeTenant(query)
+
+	if err != nil {
+		repository.latestError = err
+		panic("Repository[" + method + "]: " + err.Error())
+	}
+
+	return scopedQuery
+}
+
+// assignTenant
+// Sets the tenant of the repository on a model about to be created
+func (repository *Repository[T]) assignTenant(value *T) error {
+	if repository.tenancy.disabled || repository.tenancy.strategy != TenancyColumn {
+		return nil
+	}
+
+	field := repository.tenantField()
+
+	if field == nil {
+		return nil
+	}
+
+	if repository.tenancy.tenant == nil {
+		return ErrMissingTenant
+	}
+
+	return field.Set(context.Background(), reflect.ValueOf(value).Elem(), repository.tenancy.tenant)
+}
@@ -0,0 +1,90 @@
+package Repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type union[T any] struct {
+	all     bool
+	builder *QueryBuilder[T]
+}
+
+// Union
+// Combines the results of the query with those of another,
+// leaving out duplicates. Ordering and limits of this query
+// apply to the combined results, those of the other query
+// only apply to its own results
+func (builder *QueryBuilder[T]) Union(other *QueryBuilder[T]) *QueryBuilder[T] {
+	builder.unions = append(builder.unions, union[T]{all: false, builder: other})
+
+	return builder
+}
+
+// UnionAll
+// Combines the results of the query with those of another,
+// keeping duplicates. Ordering and limits of this query
+// apply to the combined results, those of the other query
+// only apply to its own results
+func (builder *QueryBuilder[T]) UnionAll(other *QueryBuilder[T]) *QueryBuilder[T] {
+	builder.unions = append(builder.unions, union[T]{all: true, builder: other})
+
+	return builder
+}
+
+// compiled
+// Gets the query to execute. For unions, the queries are combined
+// into a derived table named as the table of the model, so columns
+// qualified with the table name keep working, and the ordering
+// and limits of this query are lifted onto the combined results
+func (builder *QueryBuilder[T]) compiled() *gorm.DB {
+	if len(builder.unions) == 0 {
+		return builder.query
+	}
+
+	queryContext := builder.query.Statement.Context
+
+	if queryContext == nil {
+		queryContext = context.Background()
+	}
+
+	base := builder.query.Session(&gorm.Session{Context: queryContext}).Model(builder.model)
+	lifted := map[string]clause.Clause{}
+
+	for _, name := range []string{"ORDER BY", "LIMIT"} {
+		if liftedClause, exists := base.Statement.Clauses[name]; exists {
+			lifted[name] = liftedClause
+			delete(base.Statement.Clauses, name)
+		}
+	}
+
+	sql := "SELECT * FROM (?)"
+	vars := []any{base}
+
+	for _, combined := range builder.unions {
+		if combined.all {
+			sql += " UNION ALL SELECT * FROM (?)"
+		} else {
+			sql += " UNION SELECT * FROM (?)"
+		}
+
+		vars = append(vars, combined.builder.compiled().Model(combined.builder.model))
+	}
+
+	vars = append(vars, clause.Table{Name: builder.table()})
+
+	query := builder.query.
+		Session(&gorm.Session{NewDB: true, Context: queryContext}).
+		Table("("+sql+") AS ?", vars...)
+
+	// Lifted clauses are added as a whole, keeping custom builders
+	// like the one writing orderings added with OrderByRaw
+	for name, liftedClause := range lifted {
+		query.Statement.Clauses[name] = liftedClause
+	}
+
+	query.Statement.Preloads = base.Statement.Preloads
+
+	return query
+}
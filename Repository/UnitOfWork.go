@@ -0,0 +1,487 @@
+package Repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+type entryState int
+
+const (
+	entryClean entryState = iota
+	entryNew
+	entryDeleted
+)
+
+// ErrUnitSpansConnections
+// Returned when committing a unit of work holding models of different
+// connections, as they cannot be written within a single transaction
+var ErrUnitSpansConnections = errors.New("UnitOfWork: Models of different connections cannot be committed together")
+
+type UnitOfWork struct {
+	mutex    sync.Mutex
+	config   []Config
+	entries  map[identityKey]*unitEntry
+	sequence int
+}
+
+// identityKey
+// Identifies a model by its type and primary key
+type identityKey struct {
+	modelType reflect.Type
+	id        any
+}
+
+// unitEntry
+// A model tracked by a unit of work. The operations flushing the
+// model are captured when tracking it, as that is where its type
+// is known, so they go through a Repository of the right type
+type unitEntry struct {
+	model      any
+	schema     *schema.Schema
+	snapshot   map[string]any
+	state      entryState
+	sequence   int
+	connection *Config
+
+	changes func() map[string]any
+	insert  func(config Config) error
+	update  func(config Config, changes map[string]any) error
+	remove  func(config Config) error
+	refresh func()
+	save    func() func()
+}
+
+// NewUnitOfWork
+// Named constructor for creating a unit of work. Models are committed
+// to the connection they resolve to, unless a configuration is passed
+func NewUnitOfWork(config ...Config) *UnitOfWork {
+	return &UnitOfWork{
+		config:  config,
+		entries: map[identityKey]*unitEntry{},
+	}
+}
+
+// Track
+// Tracks a model loaded from the database. If a model of the same
+// type and primary key is already tracked, that instance is
+// returned instead, making sure there is only one per entity
+func Track[T any](unit *UnitOfWork, model *T) *T {
+	if model == nil {
+		return nil
+	}
+
+	unit.mutex.Lock()
+	defer unit.mutex.Unlock()
+
+	entry := newUnitEntry(unit, model, entryClean)
+	key := identityOf(entry.schema, model)
+
+	if tracked, exists := unit.entries[key]; exists {
+		return tracked.model.(*T)
+	}
+
+	unit.entries[key] = entry
+
+	return model
+}
+
+// TrackAll
+// Tracks all models of a slice loaded from the database
+func TrackAll[T any](unit *UnitOfWork, models []T) []*T {
+	tracked := make([]*T, 0, len(models))
+
+	for index := range models {
+		tracked = append(tracked, Track(unit, &models[index]))
+	}
+
+	return tracked
+}
+
+// Load
+// Gets a model by its id, from the identity map if tracked
+// already, otherwise from the database. Returns nil if not found
+func Load[T any](unit *UnitOfWork, id uuid.UUID) *T {
+	unit.mutex.Lock()
+	tracked, exists := unit.entries[identityKey{modelType: reflect.TypeOf(new(T)), id: id}]
+	unit.mutex.Unlock()
+
+	if exists {
+		if tracked.state == entryDeleted {
+			return nil
+		}
+
+		return tracked.model.(*T)
+	}
+
+	return Track(unit, Of[T](unit.config...).Query().Where("id = ?", id).First())
+}
+
+// Insert
+// Schedules a new model to be inserted on commit
+func Insert[T any](unit *UnitOfWork, model *T) *T {
+	unit.mutex.Lock()
+	defer unit.mutex.Unlock()
+
+	entry := newUnitEntry(unit, model, entryNew)
+	unit.entries[identityOf(entry.schema, model)] = entry
+
+	return model
+}
+
+// Delete
+// Schedules a tracked model to be deleted on commit
+func Delete[T any](unit *UnitOfWork, model *T) error {
+	unit.mutex.Lock()
+	defer unit.mutex.Unlock()
+
+	for key, entry := range unit.entries {
+		if entry.model != any(model) {
+			continue
+		}
+
+		// Models never inserted are simply forgotten
+		if entry.state == entryNew {
+			delete(unit.entries, key)
+		} else {
+			entry.state = entryDeleted
+		}
+
+		return nil
+	}
+
+	return errors.New("UnitOfWork[Delete]: Model is not tracked")
+}
+
+// IsTracked
+// Tells if a model is tracked by the unit of work
+func (unit *UnitOfWork) IsTracked(model any) bool {
+	unit.mutex.Lock()
+	defer unit.mutex.Unlock()
+
+	for _, entry := range unit.entries {
+		if entry.model == model {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Commit
+// Flushes all inserts, changes and deletes within a single
+// transaction. Inserts are ordered so models are inserted before
+// the models referencing them, and deletes the other way around.
+// If the transaction fails, the models are restored to how they
+// were before committing, so the commit can be retried
+func (unit *UnitOfWork) Commit() error {
+	unit.mutex.Lock()
+	defer unit.mutex.Unlock()
+
+	var inserts, updates, deletes []*unitEntry
+	changes := map[*unitEntry]map[string]any{}
+
+	for _, entry := range unit.entries {
+		switch entry.state {
+		case entryNew:
+			inserts = append(inserts, entry)
+		case entryDeleted:
+			deletes = append(deletes, entry)
+		default:
+			if changed := entry.changes(); len(changed) > 0 {
+				changes[entry] = changed
+				updates = append(updates, entry)
+			}
+		}
+	}
+
+	ranks := dependencyRanks(unit.schemas())
+
+	sortEntries(inserts, func(a *unitEntry, b *unitEntry) bool {
+		return ranks[a.schema.Table] < ranks[b.schema.Table]
+	})
+
+	sortEntries(updates, func(a *unitEntry, b *unitEntry) bool {
+		return ranks[a.schema.Table] < ranks[b.schema.Table]
+	})
+
+	sortEntries(deletes, func(a *unitEntry, b *unitEntry) bool {
+		return ranks[a.schema.Table] > ranks[b.schema.Table]
+	})
+
+	pending := append(append(append([]*unitEntry{}, inserts...), updates...), deletes...)
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// All models are written on the connection they resolve to,
+	// which must be the same for all of them
+	connection := pending[0].connection
+
+	for _, entry := range pending {
+		if entry.connection.DatabaseConnection != connection.DatabaseConnection {
+			return ErrUnitSpansConnections
+		}
+	}
+
+	// Inserts and updates set ids, versions and timestamps on
+	// the models, which must not outlive a rolled back transaction
+	var restores []func()
+
+	for _, entry := range append(append([]*unitEntry{}, inserts...), updates...) {
+		restores = append(restores, entry.save())
+	}
+
+	err := Transaction(func(config Config) error {
+		for _, entry := range inserts {
+			if err := entry.insert(config); err != nil {
+				return err
+			}
+		}
+
+		for _, entry := range updates {
+			if err := entry.update(config, changes[entry]); err != nil {
+				return err
+			}
+		}
+
+		for _, entry := range deletes {
+			if err := entry.remove(config); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, *connection)
+
+	if err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+
+		return err
+	}
+
+	// Once committed, the tracked models are in sync with the database
+	for key, entry := range unit.entries {
+		if entry.state == entryDeleted {
+			delete(unit.entries, key)
+
+			continue
+		}
+
+		if entry.state == entryNew {
+			delete(unit.entries, key)
+			unit.entries[identityOf(entry.schema, entry.model)] = entry
+		}
+
+		entry.state = entryClean
+		entry.refresh()
+	}
+
+	return nil
+}
+
+// schemas
+// Gets the schemas of all tracked models
+func (unit *UnitOfWork) schemas() []*schema.Schema {
+	var schemas []*schema.Schema
+
+	for _, entry := range unit.entries {
+		schemas = append(schemas, entry.schema)
+	}
+
+	return schemas
+}
+
+// newUnitEntry
+// Captures the operations flushing a model of a specific type
+func newUnitEntry[T any](unit *UnitOfWork, model *T, state entryState) *unitEntry {
+	repository := Of[T](unit.config...)
+	modelSchema := repository.schema()
+	connection, _ := resolveConfiguration(repository.model, unit.config)
+
+	unit.sequence++
+
+	entry := &unitEntry{
+		model:      model,
+		schema:     modelSchema,
+		state:      state,
+		sequence:   unit.sequence,
+		connection: connection,
+	}
+
+	entry.refresh = func() {
+		entry.snapshot = takeSnapshot(modelSchema, reflect.ValueOf(model).Elem())
+	}
+
+	entry.save = func() func() {
+		saved := *model
+
+		return func() {
+			*model = saved
+		}
+	}
+
+	entry.changes = func() map[string]any {
+		return diffSnapshot(modelSchema, reflect.ValueOf(model).Elem(), entry.snapshot)
+	}
+
+	entry.insert = func(config Config) (err error) {
+		defer recoverError(&err)
+
+		*model = *Of[T](config).Create(*model)
+
+		return nil
+	}
+
+	entry.update = func(config Config, changes map[string]any) (err error) {
+		defer recoverError(&err)
+
+		return Of[T](config).updateChanges(model, changes)
+	}
+
+	entry.remove = func(config Config) (err error) {
+		defer recoverError(&err)
+
+		Of[T](config).Query().Where("id = ?", primaryKeyOf(modelSchema, model)).Delete()
+
+		return nil
+	}
+
+	entry.refresh()
+
+	return entry
+}
+
+// identityOf
+// Gets the identity of a model. Models without a primary key
+// yet are identified by their pointer until inserted
+func identityOf(modelSchema *schema.Schema, model any) identityKey {
+	key := identityKey{modelType: reflect.TypeOf(model)}
+
+	if modelSchema.PrioritizedPrimaryField == nil {
+		key.id = model
+
+		return key
+	}
+
+	id, isZero := modelSchema.PrioritizedPrimaryField.ValueOf(context.Background(), reflect.ValueOf(model).Elem())
+
+	if isZero {
+		key.id = model
+	} else {
+		key.id = id
+	}
+
+	return key
+}
+
+// primaryKeyOf
+// Gets the uuid primary key of a model
+func primaryKeyOf(modelSchema *schema.Schema, model any) uuid.UUID {
+	id, _ := modelSchema.PrioritizedPrimaryField.ValueOf(context.Background(), reflect.ValueOf(model).Elem())
+
+	return id.(uuid.UUID)
+}
+
+// dependencyRanks
+// Ranks tables so tables are ranked higher than the tables their
+// foreign keys reference. Cycles are broken arbitrarily
+func dependencyRanks(schemas []*schema.Schema) map[string]int {
+	dependencies := map[string]map[string]bool{}
+
+	for _, modelSchema := range schemas {
+		if dependencies[modelSchema.Table] == nil {
+			dependencies[modelSchema.Table] = map[string]bool{}
+		}
+
+		for _, relationship := range modelSchema.Relationships.Relations {
+			for _, reference := range relationship.References {
+				// Primary keys referencing other tables are relationships
+				// declared the wrong way around, so they are skipped
+				if reference.PrimaryKey == nil || reference.ForeignKey.PrimaryKey {
+					continue
+				}
+
+				dependent := reference.ForeignKey.Schema.Table
+				dependency := reference.PrimaryKey.Schema.Table
+
+				if dependent == dependency {
+					continue
+				}
+
+				if dependencies[dependent] == nil {
+					dependencies[dependent] = map[string]bool{}
+				}
+
+				dependencies[dependent][dependency] = true
+			}
+		}
+	}
+
+	ranks := map[string]int{}
+	visiting := map[string]bool{}
+
+	var rank func(table string) int
+
+	rank = func(table string) int {
+		if value, ranked := ranks[table]; ranked {
+			return value
+		}
+
+		if visiting[table] {
+			return 0
+		}
+
+		visiting[table] = true
+		value := 0
+
+		for dependency := range dependencies[table] {
+			if dependencyRank := rank(dependency) + 1; dependencyRank > value {
+				value = dependencyRank
+			}
+		}
+
+		ranks[table] = value
+
+		return value
+	}
+
+	for table := range dependencies {
+		rank(table)
+	}
+
+	return ranks
+}
+
+// sortEntries
+// Sorts entries, keeping the order they were tracked in otherwise
+func sortEntries(entries []*unitEntry, less func(a *unitEntry, b *unitEntry) bool) {
+	sort.SliceStable(entries, func(i int, j int) bool {
+		if less(entries[i], entries[j]) {
+			return true
+		}
+
+		if less(entries[j], entries[i]) {
+			return false
+		}
+
+		return entries[i].sequence < entries[j].sequence
+	})
+}
+
+// recoverError
+// Turns a panic into an error
+func recoverError(err *error) {
+	if recovered := recover(); recovered != nil {
+		*err = errors.New(fmt.Sprint(recovered))
+	}
+}
@@ -0,0 +1,144 @@
+package Repository
+
+import (
+	"errors"
+	"github.com/nbj/go-support/Support"
+	"gorm.io/gorm/schema"
+	"reflect"
+)
+
+// ErrStaleModel
+// Returned by Update when the version of a versioned model
+// no longer matches the version stored in the database
+var ErrStaleModel = errors.New("Repository[Update]: Model is stale")
+
+// ErrMissingVersion
+// Returned by Update when a versioned model is updated without
+// passing along the version the update is based upon
+var ErrMissingVersion = errors.New("Repository[Update]: No version passed for versioned model")
+
+// versionTag
+// The struct tag value marking a field as the version column
+const versionTag = "version"
+
+type WithVersion interface {
+	VersionColumn() string
+}
+
+// versionField
+// Finds the schema field used for optimistic locking, either
+// declared by the WithVersion interface or tagged with
+// `repository:"version"`. Returns nil for unversioned models
+func (repository *Repository[T]) versionField() *schema.Field {
+	modelSchema := repository.schema()
+
+	if Support.Implements[WithVersion](repository.model) {
+		return modelSchema.LookUpField(Support.Cast[WithVersion](repository.model).VersionColumn())
+	}
+
+	for _, field := range modelSchema.Fields {
+		if field.Tag.Get("repository") == versionTag {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// initializeVersion
+// Versioned models start out at version 1 when created
+func (repository *Repository[T]) initializeVersion(value *T) {
+	versionField := repository.versionField()
+
+	if versionField == nil {
+		return
+	}
+
+	fieldValue := reflect.ValueOf(value).Elem().FieldByIndex(versionField.StructField.Index)
+
+	if current, ok := toInt64(fieldValue); ok && current == 0 {
+		if fieldValue.CanInt() {
+			fieldValue.SetInt(1)
+		} else {
+			fieldValue.SetUint(1)
+		}
+	}
+}
+
+// extractVersion
+// Reads the version an update is based upon from the values passed
+// to Update and replaces it with the incremented version
+func extractVersion(field *schema.Field, values any) (any, int64, bool) {
+	switch typed := values.(type) {
+	case map[string]any:
+		for _, key := range []string{field.DBName, field.Name} {
+			if version, ok := typed[key]; ok {
+				current, isNumeric := toInt64(reflect.ValueOf(version))
+
+				if !isNumeric {
+					return values, 0, false
+				}
+
+				incremented := make(map[string]any, len(typed))
+
+				for name, value := range typed {
+					incremented[name] = value
+				}
+
+				delete(incremented, field.Name)
+				incremented[field.DBName] = current + 1
+
+				return incremented, current, true
+			}
+		}
+
+		return values, 0, false
+	}
+
+	value := reflect.ValueOf(values)
+
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return values, 0, false
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return values, 0, false
+	}
+
+	fieldValue := value.FieldByIndex(field.StructField.Index)
+	current, isNumeric := toInt64(fieldValue)
+
+	if !isNumeric || current == 0 {
+		return values, 0, false
+	}
+
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+
+	incremented := copied.FieldByIndex(field.StructField.Index)
+
+	if incremented.CanInt() {
+		incremented.SetInt(current + 1)
+	} else {
+		incremented.SetUint(uint64(current + 1))
+	}
+
+	return copied.Addr().Interface(), current, true
+}
+
+// toInt64
+// Converts any integer value to an int64
+func toInt64(value reflect.Value) (int64, bool) {
+	switch {
+	case value.CanInt():
+		return value.Int(), true
+	case value.CanUint():
+		return int64(value.Uint()), true
+	default:
+		return 0, false
+	}
+}
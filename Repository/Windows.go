@@ -0,0 +1,165 @@
+package Repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+// windowFunctions
+// The functions without arguments allowed by SelectWindow
+var windowFunctions = map[string]bool{
+	"row_number": true, "rank": true, "dense_rank": true, "percent_rank": true, "cume_dist": true,
+}
+
+// latestPerGroupColumn
+// The column numbering rows within their group for LatestPerGroup
+const latestPerGroupColumn = "latest_per_group"
+
+// SelectWindow
+// Adds a window function to the selected columns, computed over rows
+// partitioned by partitionBy and ordered by orderBy. Both take comma
+// separated columns, with orderBy optionally followed by asc or desc,
+// and may be left empty. Supported functions are row_number, rank,
+// dense_rank, percent_rank and cume_dist
+func (builder *QueryBuilder[T]) SelectWindow(function string, partitionBy string, orderBy string, alias string) *QueryBuilder[T] {
+	function = strings.ToLower(strings.TrimSpace(function))
+
+	if !windowFunctions[function] {
+		panic("QueryBuilder[SelectWindow]: Unsupported window function " + function)
+	}
+
+	return builder.selectWindow("SelectWindow", strings.ToUpper(function)+"()", nil, partitionBy, orderBy, "", alias)
+}
+
+// Rank
+// Adds the rank of each row within its partition to the selected columns
+func (builder *QueryBuilder[T]) Rank(partitionBy string, orderBy string, alias string) *QueryBuilder[T] {
+	return builder.selectWindow("Rank", "RANK()", nil, partitionBy, orderBy, "", alias)
+}
+
+// Lag
+// Adds the value of column in the previous row of the partition to the selected columns
+func (builder *QueryBuilder[T]) Lag(column string, partitionBy string, orderBy string, alias string) *QueryBuilder[T] {
+	return builder.selectWindow("Lag", "LAG(?)", []any{clause.Column{Name: column}}, partitionBy, orderBy, "", alias)
+}
+
+// Lead
+// Adds the value of column in the next row of the partition to the selected columns
+func (builder *QueryBuilder[T]) Lead(column string, partitionBy string, orderBy string, alias string) *QueryBuilder[T] {
+	return builder.selectWindow("Lead", "LEAD(?)", []any{clause.Column{Name: column}}, partitionBy, orderBy, "", alias)
+}
+
+// SumOver
+// Adds the sum of column over the partition to the selected columns.
+// When ordered, the sum is a running total up to and including each row
+func (builder *QueryBuilder[T]) SumOver(column string, partitionBy string, orderBy string, alias string) *QueryBuilder[T] {
+	frame := ""
+
+	if strings.TrimSpace(orderBy) != "" {
+		frame = " ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW"
+	}
+
+	return builder.selectWindow("SumOver", "SUM(?)", []any{clause.Column{Name: column}}, partitionBy, orderBy, frame, alias)
+}
+
+// LatestPerGroup
+// Limits the query to the row with the highest value of orderColumn
+// for every distinct value of groupColumn. Conditions added before
+// are applied to the rows of the groups, those added after are
+// applied to the latest rows
+func (builder *QueryBuilder[T]) LatestPerGroup(groupColumn string, orderColumn string) *QueryBuilder[T] {
+	table := builder.table()
+
+	queryContext := builder.query.Statement.Context
+
+	if queryContext == nil {
+		queryContext = context.Background()
+	}
+
+	builder.selectWindow("LatestPerGroup", "ROW_NUMBER()", nil, groupColumn, orderColumn+" desc", "", latestPerGroupColumn)
+
+	preloads := builder.query.Statement.Preloads
+	grouped := builder.query.Session(&gorm.Session{Context: queryContext}).Model(builder.model)
+
+	builder.query = builder.query.
+		Session(&gorm.Session{NewDB: true, Context: queryContext}).
+		Table("(?) AS ?", grouped, clause.Table{Name: table}).
+		Where("? = 1", clause.Column{Table: table, Name: latestPerGroupColumn})
+
+	builder.query.Statement.Preloads = preloads
+	builder.selects = nil
+	builder.from = table
+
+	return builder
+}
+
+// selectWindow
+// Adds a window function call to the selected columns
+func (builder *QueryBuilder[T]) selectWindow(method string, function string, args []any, partitionBy string, orderBy string, frame string, alias string) *QueryBuilder[T] {
+	over, vars := windowDefinition(method, partitionBy, orderBy)
+
+	builder.addSelect(clause.Expr{
+		SQL:  function + " OVER (" + over + frame + ") AS ?",
+		Vars: append(append(args, vars...), clause.Column{Name: alias}),
+	})
+
+	return builder
+}
+
+// windowDefinition
+// Compiles the PARTITION BY and ORDER BY parts of a window
+func windowDefinition(method string, partitionBy string, orderBy string) (string, []any) {
+	var parts []string
+	var vars []any
+
+	if partitions := splitList(partitionBy); len(partitions) > 0 {
+		placeholders := make([]string, 0, len(partitions))
+
+		for _, column := range partitions {
+			placeholders = append(placeholders, "?")
+			vars = append(vars, clause.Column{Name: column})
+		}
+
+		parts = append(parts, "PARTITION BY "+strings.Join(placeholders, ", "))
+	}
+
+	if orders := splitList(orderBy); len(orders) > 0 {
+		placeholders := make([]string, 0, len(orders))
+
+		for _, order := range orders {
+			fields := strings.Fields(order)
+			direction := "ASC"
+
+			if len(fields) == 2 {
+				direction = strings.ToUpper(fields[1])
+			}
+
+			if len(fields) > 2 || (direction != "ASC" && direction != "DESC") {
+				panic("QueryBuilder[" + method + "]: Invalid ordering " + order)
+			}
+
+			placeholders = append(placeholders, "? "+direction)
+			vars = append(vars, clause.Column{Name: fields[0]})
+		}
+
+		parts = append(parts, "ORDER BY "+strings.Join(placeholders, ", "))
+	}
+
+	return strings.Join(parts, " "), vars
+}
+
+// splitList
+// Splits a comma separated list, skipping empty entries
+func splitList(list string) []string {
+	var result []string
+
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
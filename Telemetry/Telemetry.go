@@ -0,0 +1,116 @@
+package Telemetry
+
+import (
+	"context"
+	"github.com/nbj/go-repository/Repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+// InstrumentationName
+// The name spans and metrics are emitted under
+const InstrumentationName = "github.com/nbj/go-repository"
+
+type Options struct {
+	// The provider spans are created with. Defaults to the global provider
+	TracerProvider trace.TracerProvider
+
+	// The provider metrics are recorded with. Defaults to the global provider
+	MeterProvider metric.MeterProvider
+}
+
+// Telemetry
+// Instrumentation emitting an OpenTelemetry span per operation, and
+// recording the number of operations, their duration and the number
+// of failed operations, by method and model
+type Telemetry struct {
+	tracer     trace.Tracer
+	operations metric.Int64Counter
+	duration   metric.Float64Histogram
+	errors     metric.Int64Counter
+}
+
+// Of
+// Named constructor for creating instances of the instrumentation.
+// Panics if the metric instruments cannot be created
+func Of(options ...Options) *Telemetry {
+	var resolved Options
+
+	if len(options) > 0 {
+		resolved = options[0]
+	}
+
+	if resolved.TracerProvider == nil {
+		resolved.TracerProvider = otel.GetTracerProvider()
+	}
+
+	if resolved.MeterProvider == nil {
+		resolved.MeterProvider = otel.GetMeterProvider()
+	}
+
+	meter := resolved.MeterProvider.Meter(InstrumentationName)
+
+	var telemetry Telemetry
+	var err error
+
+	telemetry.tracer = resolved.TracerProvider.Tracer(InstrumentationName)
+
+	if telemetry.operations, err = meter.Int64Counter("repository.operations",
+		metric.WithDescription("The number of operations performed"),
+		metric.WithUnit("{operation}"),
+	); err != nil {
+		panic("Telemetry[Of]: " + err.Error())
+	}
+
+	if telemetry.duration, err = meter.Float64Histogram("repository.operation.duration",
+		metric.WithDescription("The duration of operations"),
+		metric.WithUnit("s"),
+	); err != nil {
+		panic("Telemetry[Of]: " + err.Error())
+	}
+
+	if telemetry.errors, err = meter.Int64Counter("repository.operation.errors",
+		metric.WithDescription("The number of operations that failed"),
+		metric.WithUnit("{operation}"),
+	); err != nil {
+		panic("Telemetry[Of]: " + err.Error())
+	}
+
+	return &telemetry
+}
+
+// Start
+// Starts a span for the operation. Once the operation has finished the
+// span is ended, marked as failed if it did, and its metrics are recorded
+func (telemetry *Telemetry) Start(ctx context.Context, operation Repository.Operation) (context.Context, func(err error)) {
+	attributes := []attribute.KeyValue{attribute.String("repository.method", operation.Method)}
+
+	if operation.Model != "" {
+		attributes = append(attributes, attribute.String("repository.model", operation.Model))
+	}
+
+	started := time.Now()
+	ctx, span := telemetry.tracer.Start(ctx, operation.Method,
+		trace.WithAttributes(attributes...),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+
+	return ctx, func(err error) {
+		set := metric.WithAttributes(attributes...)
+
+		telemetry.operations.Add(ctx, 1, set)
+		telemetry.duration.Record(ctx, time.Since(started).Seconds(), set)
+
+		if err != nil {
+			telemetry.errors.Add(ctx, 1, set)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}
+}
@@ -0,0 +1,207 @@
+package Feature
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func cacheConfig(cache Repository.Cache, ttl time.Duration) Repository.Config {
+	config, _ := Repository.Connection(Repository.DefaultConnection)
+
+	cached := *config
+	cached.Cache = cache
+	cached.CacheTTL = ttl
+
+	return cached
+}
+
+// changeBehindTheRepository
+// Writes directly to the database, bypassing cache invalidation
+func changeBehindTheRepository(config Repository.Config, from string, to string) {
+	config.DatabaseConnection.
+		Model(&Tests.TestCaseModel{}).
+		Where("value = ?", from).
+		Update("value", to)
+}
+
+func Test_query_builder_can_remember_results(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config := cacheConfig(Repository.NewMemoryCache(10), 0)
+	repository := Repository.Of[Tests.TestCaseModel](config)
+
+	first := repository.Query().Where("value", "Value [1]").Remember(time.Minute).Get()
+	changeBehindTheRepository(config, "Value [1]", "Value [CHANGED]")
+
+	// Act
+	remembered := repository.Query().Where("value", "Value [1]").Remember(time.Minute).Get()
+	fresh := repository.Query().Where("value", "Value [1]").Get()
+
+	// Assert
+	assert.Equal(t, 1, first.Count())
+	assert.Equal(t, 1, remembered.Count())
+	assert.Equal(t, "Value [1]", remembered.First().Value)
+	assert.Equal(t, 0, fresh.Count())
+}
+
+func Test_cache_keys_depend_on_the_sql_and_bindings(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	cache := Repository.NewMemoryCache(10)
+	repository := Repository.Of[Tests.TestCaseModel](cacheConfig(cache, 0))
+
+	// Act
+	entryA := repository.Query().Where("value", "Value [1]").Remember(time.Minute).First()
+	entryB := repository.Query().Where("value", "Value [2]").Remember(time.Minute).First()
+	entries := repository.Query().Where("value", "Value [1]").Remember(time.Minute).Get()
+
+	// Assert
+	assert.Equal(t, "Value [1]", entryA.Value)
+	assert.Equal(t, "Value [2]", entryB.Value)
+	assert.Equal(t, 1, entries.Count())
+	assert.Equal(t, 3, cache.Len())
+}
+
+func Test_repository_first_is_cached_when_configured(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config := cacheConfig(Repository.NewMemoryCache(10), time.Minute)
+	repository := Repository.Of[Tests.TestCaseModel](config)
+	byValue := func(query *gorm.DB) *gorm.DB {
+		return query.Where("value = ?", "Value [2]")
+	}
+
+	first := repository.First(byValue)
+	changeBehindTheRepository(config, "Value [2]", "Value [CHANGED]")
+
+	// Act
+	remembered := repository.First(byValue)
+
+	// Assert
+	assert.Equal(t, first.Id, remembered.Id)
+	assert.Equal(t, "Value [2]", remembered.Value)
+	assert.Equal(t, 1, len(remembered.TestCaseRelationModels))
+}
+
+func Test_writes_through_the_repository_invalidate_cached_results(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	cache := Repository.NewMemoryCache(10)
+	repository := Repository.Of[Tests.TestCaseModel](cacheConfig(cache, 0))
+	query := func() int {
+		return repository.Query().Remember(time.Minute).Get().Count()
+	}
+
+	// Act & Assert
+	assert.Equal(t, 5, query())
+
+	id, _ := uuid.NewV7()
+	repository.Create(Tests.TestCaseModel{Id: id, Value: "Value [NEW]"})
+	assert.Equal(t, 6, query())
+
+	repository.Query().Where("value", "Value [NEW]").Delete()
+	assert.Equal(t, 5, query())
+
+	changed := func() *Tests.TestCaseModel {
+		return repository.Query().Where("value", "Value [1]").Remember(time.Minute).First()
+	}
+	assert.NotNil(t, changed())
+
+	repository.Update(changed().Id, map[string]any{"value": "Value [UPDATED]"})
+	assert.Nil(t, changed())
+}
+
+func Test_writes_to_other_models_do_not_invalidate_cached_results(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	cache := Repository.NewMemoryCache(10)
+	config := cacheConfig(cache, 0)
+	Repository.Of[Tests.TestCaseModel](config).Query().Remember(time.Minute).Get()
+
+	// Act
+	id, _ := uuid.NewV7()
+	Repository.Of[Tests.TestCaseRelationModel](config).Create(Tests.TestCaseRelationModel{Id: id})
+
+	// Assert
+	assert.Equal(t, 1, cache.Len())
+}
+
+func Test_writes_inside_transactions_invalidate_cached_results(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	cache := Repository.NewMemoryCache(10)
+	config := cacheConfig(cache, 0)
+	Repository.Of[Tests.TestCaseModel](config).Query().Remember(time.Minute).Get()
+
+	// Act
+	err := Repository.Transaction(func(transactionConfig Repository.Config) error {
+		id, _ := uuid.NewV7()
+		transaction := Repository.Of[Tests.TestCaseModel](transactionConfig)
+		transaction.Create(Tests.TestCaseModel{Id: id, Value: "Value [NEW]"})
+
+		// Reads inside transactions are never cached
+		transaction.Query().Remember(time.Minute).Get()
+
+		return nil
+	}, config)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, cache.Len())
+	assert.Equal(t, 6, Repository.Of[Tests.TestCaseModel](config).Query().Remember(time.Minute).Get().Count())
+}
+
+func Test_memory_cache_evicts_least_recently_used_entries(t *testing.T) {
+	// Arrange
+	cache := Repository.NewMemoryCache(2)
+
+	cache.Set("a", []byte("A"), time.Minute)
+	cache.Set("b", []byte("B"), time.Minute)
+	cache.Get("a")
+
+	// Act
+	cache.Set("c", []byte("C"), time.Minute)
+
+	// Assert
+	_, hasA := cache.Get("a")
+	_, hasB := cache.Get("b")
+	_, hasC := cache.Get("c")
+
+	assert.True(t, hasA)
+	assert.False(t, hasB)
+	assert.True(t, hasC)
+}
+
+func Test_memory_cache_expires_and_invalidates_entries(t *testing.T) {
+	// Arrange
+	cache := Repository.NewMemoryCache(10)
+
+	cache.Set("expiring", []byte("A"), time.Millisecond)
+	cache.Set("tagged", []byte("B"), time.Minute, "models")
+	cache.Set("untagged", []byte("C"), time.Minute)
+
+	// Act
+	time.Sleep(5 * time.Millisecond)
+	cache.Invalidate("models")
+
+	// Assert
+	_, hasExpiring := cache.Get("expiring")
+	_, hasTagged := cache.Get("tagged")
+	value, hasUntagged := cache.Get("untagged")
+
+	assert.False(t, hasExpiring)
+	assert.False(t, hasTagged)
+	assert.True(t, hasUntagged)
+	assert.Equal(t, []byte("C"), value)
+}
@@ -0,0 +1,155 @@
+package Feature
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_models_loaded_through_the_repository_are_clean(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]().Track()
+
+	// Act
+	first := repository.First()
+	built := repository.Query().Where("value", "Value [2]").First()
+	all := repository.All()
+	entry := all.Get(2)
+
+	// Assert
+	assert.False(t, repository.IsDirty(first))
+	assert.False(t, repository.IsDirty(built))
+	assert.False(t, repository.IsDirty(&entry))
+	assert.Empty(t, repository.GetChanges(first))
+}
+
+func Test_changed_models_are_dirty(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]().Track()
+	model := repository.Query().Where("value", "Value [1]").First()
+
+	// Act
+	model.Value = "Value [CHANGED]"
+
+	// Assert
+	assert.True(t, repository.IsDirty(model))
+	assert.True(t, repository.IsDirty(model, "Value"))
+	assert.True(t, repository.IsDirty(model, "value"))
+	assert.False(t, repository.IsDirty(model, "CreatedAt"))
+	assert.Equal(t, map[string]any{"value": "Value [CHANGED]"}, repository.GetChanges(model))
+}
+
+func Test_models_not_loaded_through_the_repository_are_dirty(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]().Track()
+	id, _ := uuid.NewV7()
+
+	// Act
+	model := Tests.TestCaseModel{Id: id, Value: "Value [NEW]"}
+
+	// Assert
+	assert.True(t, repository.IsDirty(&model))
+	assert.Equal(t, "Value [NEW]", repository.GetChanges(&model)["value"])
+}
+
+func Test_saving_changes_only_updates_changed_columns(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]().Track()
+	model := repository.First()
+	otherId, _ := uuid.NewV7()
+
+	// Someone else changes another column in the meantime
+	Repository.Of[Tests.TestCaseRelationModel]().Update(model.Id, map[string]any{"test_case_model_id": otherId})
+
+	// Act
+	model.Value = "Value [CHANGED]"
+	err := repository.SaveChanges(model)
+
+	// Assert
+	stored := Repository.Of[Tests.TestCaseRelationModel]().Query().Where("id = ?", model.Id).First()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Value [CHANGED]", stored.Value)
+	assert.Equal(t, otherId, stored.TestCaseModelId)
+	assert.False(t, repository.IsDirty(model))
+}
+
+func Test_saving_changes_of_a_versioned_model_increments_its_version(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	id, _ := uuid.NewV7()
+	Repository.Of[Tests.TestCaseVersionedModel]().Create(Tests.TestCaseVersionedModel{Id: id, Value: "Value [NEW]"})
+
+	repository := Repository.Of[Tests.TestCaseVersionedModel]().Track()
+	model := repository.First()
+
+	// Act
+	model.Value = "Value [CHANGED]"
+	err := repository.SaveChanges(model)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, model.Version)
+	assert.False(t, repository.IsDirty(model))
+	assert.Equal(t, "Value [CHANGED]", Repository.Of[Tests.TestCaseVersionedModel]().First().Value)
+}
+
+func Test_saving_a_clean_model_does_nothing(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]().Track()
+	model := repository.First()
+
+	// Act
+	err := repository.SaveChanges(model)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, repository.GetLatestError())
+}
+
+func Test_repositories_only_track_models_when_asked_to(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	model := repository.First()
+
+	// Assert
+	assert.True(t, repository.IsDirty(model))
+}
+
+func Test_forgotten_models_are_dirty(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]().Track()
+	first := repository.Query().Where("value", "Value [1]").First()
+	second := repository.Query().Where("value", "Value [2]").First()
+	third := repository.Query().Where("value", "Value [3]").First()
+
+	// Act
+	repository.Forget(first)
+	forgotten := []bool{repository.IsDirty(first), repository.IsDirty(second)}
+
+	repository.Forget()
+
+	// Assert
+	assert.Equal(t, []bool{true, false}, forgotten)
+	assert.True(t, repository.IsDirty(second))
+	assert.True(t, repository.IsDirty(third))
+}
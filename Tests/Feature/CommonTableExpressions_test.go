@@ -0,0 +1,156 @@
+package Feature
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func seedTree(repository *Repository.Repository[Tests.TestCaseTreeModel]) map[string]uuid.UUID {
+	ids := map[string]uuid.UUID{}
+
+	nodes := [][2]string{
+		{"Root", ""},
+		{"Branch [A]", "Root"},
+		{"Branch [B]", "Root"},
+		{"Leaf [A1]", "Branch [A]"},
+		{"Leaf [A2]", "Branch [A]"},
+	}
+
+	for _, node := range nodes {
+		id, _ := uuid.NewV7()
+		entry := Tests.TestCaseTreeModel{Id: id, Value: node[0]}
+
+		if node[1] != "" {
+			parentId := ids[node[1]]
+			entry.ParentId = &parentId
+		}
+
+		repository.Create(entry)
+		ids[node[0]] = id
+	}
+
+	return ids
+}
+
+func Test_query_builder_can_use_common_table_expressions(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	relations := Repository.Of[Tests.TestCaseRelationModel]().Query().
+		Select("test_case_model_id").
+		Where("value IN ?", []string{"Relation Value [1]", "Relation Value [3]"})
+
+	// Act
+	query := repository.Query().
+		WithCTE("related", relations).
+		Where("id IN (SELECT test_case_model_id FROM related)").
+		OrderBy("value", "asc")
+
+	entries := query.Get()
+	paginator := query.Paginate(1, 1, "/")
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "Value [2]", entries.First().Value)
+	assert.Equal(t, "Value [4]", entries.Last().Value)
+	assert.Equal(t, 2, paginator.Total)
+}
+
+func Test_query_builder_can_use_recursive_common_table_expressions(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseTreeModel]()
+	seedTree(repository)
+
+	anchor := repository.Query().
+		Select("id").
+		Where("value = ?", "Branch [A]")
+
+	recursive := repository.Query().
+		Join("tree", "tree.id = test_case_tree_models.parent_id").
+		Select("test_case_tree_models.id")
+
+	// Act
+	entries := repository.Query().
+		WithRecursiveCTE("tree", anchor, recursive).
+		Where("id IN (SELECT id FROM tree)").
+		OrderBy("value", "asc").
+		Get()
+
+	// Assert
+	assert.Equal(t, 3, entries.Count())
+	assert.Equal(t, "Branch [A]", entries.First().Value)
+	assert.Equal(t, "Leaf [A2]", entries.Last().Value)
+}
+
+func Test_query_builder_can_get_descendants_of_an_entry(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseTreeModel]()
+	ids := seedTree(repository)
+
+	// Act
+	descendants := repository.Query().Descendants(ids["Root"]).OrderBy("value", "asc").Get()
+	branch := repository.Query().Descendants(ids["Branch [A]"]).Get()
+	leaf := repository.Query().Descendants(ids["Leaf [A1]"]).Get()
+
+	// Assert
+	assert.Equal(t, 4, descendants.Count())
+	assert.Equal(t, "Branch [A]", descendants.First().Value)
+	assert.Equal(t, "Leaf [A2]", descendants.Last().Value)
+	assert.Equal(t, 2, branch.Count())
+	assert.Equal(t, 0, leaf.Count())
+}
+
+func Test_query_builder_can_get_ancestors_of_an_entry(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseTreeModel]()
+	ids := seedTree(repository)
+
+	// Act
+	ancestors := repository.Query().Ancestors(ids["Leaf [A2]"]).OrderBy("value", "asc").Get()
+	root := repository.Query().Ancestors(ids["Root"]).Get()
+
+	// Assert
+	assert.Equal(t, 2, ancestors.Count())
+	assert.Equal(t, "Branch [A]", ancestors.First().Value)
+	assert.Equal(t, "Root", ancestors.Last().Value)
+	assert.Equal(t, 0, root.Count())
+}
+
+func Test_hierarchy_helpers_can_be_combined(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseTreeModel]()
+	ids := seedTree(repository)
+
+	// Act
+	descendants := repository.Query().Descendants(ids["Root"]).Descendants(ids["Branch [A]"]).Get()
+	ancestors := repository.Query().Ancestors(ids["Leaf [A1]"]).Ancestors(ids["Leaf [A2]"]).OrderBy("value", "asc").Get()
+
+	// Assert
+	assert.Equal(t, 2, descendants.Count())
+	assert.Equal(t, 2, ancestors.Count())
+	assert.Equal(t, "Branch [A]", ancestors.First().Value)
+}
+
+func Test_hierarchy_helpers_require_a_parent_key(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	id, _ := uuid.NewV7()
+
+	// Act & Assert
+	assert.Panics(t, func() { repository.Query().Descendants(id) })
+	assert.Panics(t, func() { repository.Query().Ancestors(id) })
+}
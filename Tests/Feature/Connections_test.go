@@ -0,0 +1,93 @@
+package Feature
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_named_connections_can_be_registered_and_retrieved(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+	connection := Tests.SetupConnection("analytics")
+
+	// Act
+	config, err := Repository.Connection("analytics")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Same(t, connection, config.DatabaseConnection)
+}
+
+func Test_models_are_resolved_to_the_connection_they_declare(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+	connection := Tests.SetupConnection("analytics")
+
+	repository := Repository.Of[Tests.TestCaseAnalyticsModel]()
+	id, _ := uuid.NewV7()
+
+	// Act
+	repository.Create(Tests.TestCaseAnalyticsModel{Id: id, Value: "Value [NEW]"})
+
+	// Assert
+	var count int64
+	connection.Model(&Tests.TestCaseAnalyticsModel{}).Count(&count)
+
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, 1, repository.All().Count())
+	assert.Equal(t, 5, Repository.Of[Tests.TestCaseModel]().All().Count())
+}
+
+func Test_an_explicit_config_takes_precedence_over_the_declared_connection(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+	Tests.SetupConnection("analytics")
+	config, _ := Repository.Connection(Repository.DefaultConnection)
+
+	repository := Repository.Of[Tests.TestCaseAnalyticsModel](*config)
+	id, _ := uuid.NewV7()
+
+	// Act
+	repository.Create(Tests.TestCaseAnalyticsModel{Id: id, Value: "Value [NEW]"})
+
+	// Assert
+	assert.Equal(t, 0, Repository.Of[Tests.TestCaseAnalyticsModel]().All().Count())
+}
+
+func Test_making_a_repository_without_configuration_returns_an_error(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+	Repository.RemoveConnection("analytics")
+
+	// Act
+	repository, err := Repository.Make[Tests.TestCaseAnalyticsModel]()
+
+	// Assert
+	assert.Nil(t, repository)
+	assert.True(t, errors.Is(err, Repository.ErrMissingConfiguration))
+	assert.Equal(t, "Repository: No configuration available for connection \"analytics\"", err.Error())
+}
+
+func Test_making_a_repository_without_a_default_configuration_returns_an_error(t *testing.T) {
+	// Arrange
+	Repository.SetDefaultConfig(nil)
+	defer Tests.SetupEnvironment()
+
+	// Act
+	repository, err := Repository.Make[Tests.TestCaseModel]()
+	transactionErr := Repository.Transaction(func(config Repository.Config) error {
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, repository)
+	assert.True(t, errors.Is(err, Repository.ErrMissingConfiguration))
+	assert.True(t, errors.Is(transactionErr, Repository.ErrMissingConfiguration))
+	assert.Panics(t, func() {
+		Repository.Of[Tests.TestCaseModel]()
+	})
+}
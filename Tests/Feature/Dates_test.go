@@ -0,0 +1,165 @@
+package Feature
+
+import (
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// seedDates
+// Moves the creation of Value [1..5] to fixed moments in UTC
+func seedDates(repository *Repository.Repository[Tests.TestCaseModel]) {
+	moments := []time.Time{
+		time.Date(2025, time.December, 31, 23, 30, 0, 0, time.UTC),
+		time.Date(2026, time.March, 31, 23, 30, 0, 0, time.UTC),
+		time.Date(2026, time.April, 1, 9, 15, 0, 0, time.UTC),
+		time.Date(2026, time.April, 2, 18, 45, 0, 0, time.UTC),
+		time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	entries := repository.Query().OrderBy("value", "asc").Get()
+
+	for index, moment := range moments {
+		repository.Update(entries.Get(index).Id, map[string]any{"created_at": moment})
+	}
+}
+
+func Test_query_builder_can_filter_by_dates_and_years(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	seedDates(repository)
+	april := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	onDate := repository.Query().WhereDate("created_at", "=", april).Get()
+	afterDate := repository.Query().WhereDate("created_at", ">", april).Get()
+	upToDate := repository.Query().WhereDate("created_at", "<=", april).Get()
+	inYear := repository.Query().WhereYear("created_at", "=", 2026).Get()
+	notInYear := repository.Query().WhereYear("created_at", "!=", 2026).Get()
+
+	// Assert
+	assert.Equal(t, 1, onDate.Count())
+	assert.Equal(t, "Value [3]", onDate.First().Value)
+	assert.Equal(t, 2, afterDate.Count())
+	assert.Equal(t, 3, upToDate.Count())
+	assert.Equal(t, 4, inYear.Count())
+	assert.Equal(t, 1, notInYear.Count())
+}
+
+func Test_query_builder_can_filter_by_months_and_times(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	seedDates(repository)
+
+	// Act
+	inApril := repository.Query().WhereMonth("created_at", "=", time.April).Get()
+	afterApril := repository.Query().WhereMonth("created_at", ">", time.April).Get()
+	morning := repository.Query().WhereTime("created_at", "<", "12:00").Get()
+	exact := repository.Query().WhereTime("created_at", "=", "18:45:00").Get()
+
+	// Assert
+	assert.Equal(t, 2, inApril.Count())
+	assert.Equal(t, 2, afterApril.Count())
+	assert.Equal(t, 1, morning.Count())
+	assert.Equal(t, "Value [3]", morning.First().Value)
+	assert.Equal(t, "Value [4]", exact.First().Value)
+}
+
+func Test_query_builder_can_filter_before_after_and_within_last(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	seedDates(repository)
+	repository.Update(repository.Query().Where("value = ?", "Value [5]").First().Id, map[string]any{"created_at": time.Now().Add(-time.Hour)})
+	moment := time.Date(2026, time.April, 1, 9, 15, 0, 0, time.UTC)
+
+	// Act
+	before := repository.Query().WhereBefore("created_at", moment).Get()
+	after := repository.Query().WhereAfter("created_at", moment.In(time.FixedZone("UTC+2", 2*60*60))).Get()
+	recent := repository.Query().WhereWithinLast(24 * time.Hour).Get()
+
+	// Assert
+	assert.Equal(t, 2, before.Count())
+	assert.Equal(t, 2, after.Count())
+	assert.Equal(t, 1, recent.Count())
+	assert.Equal(t, "Value [5]", recent.First().Value)
+}
+
+func Test_date_helpers_use_the_configured_timezone(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	seedDates(Repository.Of[Tests.TestCaseModel]())
+	config, _ := Repository.Connection(Repository.DefaultConnection)
+	timezoneConfig := *config
+	timezoneConfig.Timezone = time.FixedZone("UTC+2", 2*60*60)
+	repository := Repository.Of[Tests.TestCaseModel](timezoneConfig)
+	april := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	// Act
+	onDate := repository.Query().WhereDate("created_at", "=", april).OrderBy("value", "asc").Get()
+	inYear := repository.Query().WhereYear("created_at", "=", 2026).Get()
+	inApril := repository.Query().WhereMonth("created_at", "=", time.April).Get()
+	evening := repository.Query().WhereTime("created_at", "=", "20:45").Get()
+
+	// Assert
+	assert.Equal(t, 2, onDate.Count())
+	assert.Equal(t, "Value [2]", onDate.First().Value)
+	assert.Equal(t, 5, inYear.Count())
+	assert.Equal(t, 3, inApril.Count())
+	assert.Equal(t, "Value [4]", evening.First().Value)
+}
+
+func Test_date_helpers_handle_timezones_observing_daylight_saving_time(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	seedDates(Repository.Of[Tests.TestCaseModel]())
+	copenhagen, err := time.LoadLocation("Europe/Copenhagen")
+
+	if err != nil {
+		t.Skip("SKIPPED: timezone data is not available")
+	}
+
+	config, _ := Repository.Connection(Repository.DefaultConnection)
+	timezoneConfig := *config
+	timezoneConfig.Timezone = copenhagen
+	repository := Repository.Of[Tests.TestCaseModel](timezoneConfig)
+
+	// Act
+	summer := repository.Query().WhereDate("created_at", "=", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)).OrderBy("value", "asc").Get()
+	winter := repository.Query().WhereYear("created_at", "=", 2026).Get()
+
+	// Assert
+	assert.Equal(t, 2, summer.Count())
+	assert.Equal(t, "Value [2]", summer.First().Value)
+	assert.Equal(t, 5, winter.Count())
+
+	assert.PanicsWithValue(t, "QueryBuilder[WhereMonth]: Timezone Europe/Copenhagen does not have a fixed offset, which sqlite requires", func() {
+		repository.Query().WhereMonth("created_at", "=", time.April)
+	})
+
+	assert.PanicsWithValue(t, "QueryBuilder[WhereTime]: Timezone Europe/Copenhagen does not have a fixed offset, which sqlite requires", func() {
+		repository.Query().WhereTime("created_at", "=", "11:15")
+	})
+}
+
+func Test_date_helpers_reject_invalid_input(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act & Assert
+	assert.Panics(t, func() { repository.Query().WhereDate("created_at", "LIKE", time.Now()) })
+	assert.Panics(t, func() { repository.Query().WhereMonth("created_at", "=", 13) })
+	assert.Panics(t, func() { repository.Query().WhereTime("created_at", "=", "25:00") })
+	assert.Panics(t, func() { repository.Query().WhereBefore("created_at; DROP", time.Now()) })
+}
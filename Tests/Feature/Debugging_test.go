@@ -0,0 +1,84 @@
+package Feature
+
+import (
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"testing"
+)
+
+func Test_query_builder_can_compile_sql_without_executing_it(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	sql, bindings := repository.Query().
+		Where("value = ?", "Value [1]").
+		OrderByDesc("created_at").
+		Take(2).
+		ToSQL()
+
+	// Assert
+	assert.Equal(t, "SELECT * FROM `test_case_models` WHERE value = ? ORDER BY `created_at` DESC LIMIT 2", sql)
+	assert.Equal(t, []any{"Value [1]"}, bindings)
+}
+
+func Test_query_builder_can_compile_sql_with_interpolated_bindings(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	sql := repository.Query().
+		Where("value = ?", "Value [1]").
+		UnionAll(repository.Query().Where("value = ?", "Value [2]")).
+		ToRawSQL()
+
+	// Assert
+	assert.Contains(t, sql, `value = "Value [1]"`)
+	assert.Contains(t, sql, "UNION ALL")
+	assert.Contains(t, sql, `value = "Value [2]"`)
+}
+
+func Test_query_builder_can_dump_sql(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	stdout := os.Stdout
+	reader, writer, _ := os.Pipe()
+	os.Stdout = writer
+
+	// Act
+	entries := repository.Query().Where("value = ?", "Value [3]").Dump().Get()
+
+	writer.Close()
+	os.Stdout = stdout
+	output, _ := io.ReadAll(reader)
+
+	// Assert
+	assert.Equal(t, 1, entries.Count())
+	assert.Contains(t, string(output), `WHERE value = "Value [3]"`)
+}
+
+func Test_query_builder_can_explain_queries(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	scan := repository.Query().Where("value = ?", "Value [1]").Explain()
+	search := repository.Query().Where("id = ?", repository.First().Id).Explain()
+
+	// Assert
+	assert.Contains(t, scan.SQL, `"Value [1]"`)
+	assert.NotEmpty(t, scan.Rows)
+	assert.Contains(t, scan.String(), "SCAN test_case_models")
+	assert.Contains(t, search.String(), "SEARCH test_case_models USING INDEX")
+}
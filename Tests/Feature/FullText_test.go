@@ -0,0 +1,144 @@
+package Feature
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testCaseRelevanceModel struct {
+	Value     string
+	Relevance float64
+}
+
+// setupFullTextIndex
+// FTS5 is only compiled into the sqlite driver when building with the
+// sqlite_fts5 tag. Without it, tests using the index are skipped, so
+// run the tests with go test -tags sqlite_fts5 ./... as well
+func setupFullTextIndex(t *testing.T) *Repository.Repository[Tests.TestCaseModel] {
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	if err := repository.CreateFullTextIndex("value"); err != nil {
+		if strings.Contains(err.Error(), "no such module: fts5") {
+			t.Skip("SKIPPED: the sqlite driver is built without FTS5, run go test -tags sqlite_fts5 ./... to include full-text tests")
+		}
+
+		t.Fatal(err)
+	}
+
+	return repository
+}
+
+func Test_query_builder_can_search_using_full_text(t *testing.T) {
+	// Arrange
+	repository := setupFullTextIndex(t)
+
+	// Act
+	entries := repository.Query().WhereFullText([]string{"value"}, "value 3").Get()
+	none := repository.Query().WhereFullText([]string{"value"}, "missing").Get()
+	all := repository.Query().WhereFullText([]string{"value"}, "  ").Get()
+
+	// Assert
+	assert.Equal(t, 1, entries.Count())
+	assert.Equal(t, "Value [3]", entries.First().Value)
+	assert.Equal(t, 0, none.Count())
+	assert.Equal(t, 5, all.Count())
+}
+
+func Test_full_text_index_is_kept_up_to_date(t *testing.T) {
+	// Arrange
+	repository := setupFullTextIndex(t)
+	id, _ := uuid.NewV7()
+	repository.Create(Tests.TestCaseModel{Id: id, Value: "Searchable entry"})
+	repository.Update(repository.Query().Where("value = ?", "Value [1]").First().Id, map[string]any{"value": "Renamed entry"})
+	repository.Query().Where("value = ?", "Value [2]").Delete()
+
+	// Act
+	entries := repository.Query().WhereFullText([]string{"value"}, "entry").OrderBy("value", "asc").Get()
+	renamed := repository.Query().WhereFullText([]string{"value"}, "1").Get()
+	deleted := repository.Query().WhereFullText([]string{"value"}, "2").Get()
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "Renamed entry", entries.First().Value)
+	assert.Equal(t, 0, renamed.Count())
+	assert.Equal(t, 0, deleted.Count())
+}
+
+func Test_full_text_index_survives_vacuuming(t *testing.T) {
+	// Arrange
+	repository := setupFullTextIndex(t)
+	repository.Query().Where("value IN ?", []string{"Value [1]", "Value [2]"}).Delete()
+
+	_, err := repository.Exec("VACUUM")
+
+	// Act
+	entries := repository.Query().WhereFullText([]string{"value"}, "value 4").OrderByRelevance().Get()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, entries.Count())
+	assert.Equal(t, "Value [4]", entries.First().Value)
+}
+
+func Test_query_builder_can_order_full_text_results_by_relevance(t *testing.T) {
+	// Arrange
+	repository := setupFullTextIndex(t)
+
+	for _, value := range []string{"Apple", "Apple apple apple pie"} {
+		id, _ := uuid.NewV7()
+		repository.Create(Tests.TestCaseModel{Id: id, Value: value})
+	}
+
+	// Act
+	entries := repository.Query().WhereFullText([]string{"value"}, "apple").OrderByRelevance().Get()
+	projected := Repository.Project[testCaseRelevanceModel](repository.Query().
+		WhereFullText([]string{"value"}, "apple").
+		OrderByRelevance())
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "Apple apple apple pie", entries.First().Value)
+	assert.Greater(t, projected.First().Relevance, projected.Last().Relevance)
+}
+
+func Test_full_text_index_is_created_on_the_table_of_the_tenant(t *testing.T) {
+	// Arrange
+	setupFullTextIndex(t)
+
+	config := tenantConfig("tenant_a", Repository.TenancySchema)
+	attachTenantSchema(config, "tenant_a")
+
+	repository := Repository.Of[Tests.TestCaseModel](config)
+	id, _ := uuid.NewV7()
+	repository.Create(Tests.TestCaseModel{Id: id, Value: "Value [TENANT]"})
+
+	// Act
+	err := repository.CreateFullTextIndex("value")
+
+	// Assert
+	entries := repository.Query().WhereFullText([]string{"value"}, "value").Get()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, entries.Count())
+	assert.Equal(t, id, entries.First().Id)
+	assert.Equal(t, 5, Repository.Of[Tests.TestCaseModel]().Query().WhereFullText([]string{"value"}, "value").Get().Count())
+}
+
+func Test_full_text_search_rejects_invalid_input(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act & Assert
+	assert.Panics(t, func() { repository.Query().WhereFullText([]string{"does_not_exist"}, "term") })
+	assert.Panics(t, func() { repository.Query().WhereFullText(nil, "term") })
+	assert.Panics(t, func() { repository.Query().OrderByRelevance() })
+	assert.Panics(t, func() { _ = repository.CreateFullTextIndex("does_not_exist") })
+}
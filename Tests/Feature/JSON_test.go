@@ -0,0 +1,118 @@
+package Feature
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func seedDocuments(repository *Repository.Repository[Tests.TestCaseDocumentModel]) {
+	documents := []Tests.TestCaseDocumentMeta{
+		{Address: Tests.TestCaseDocumentAddress{City: "Aarhus", Country: "DK"}, Score: 3, Tags: []string{"a", "b"}},
+		{Address: Tests.TestCaseDocumentAddress{City: "Copenhagen", Country: "DK"}, Score: 1, Tags: []string{"b"}},
+		{Address: Tests.TestCaseDocumentAddress{City: "Berlin", Country: "DE"}, Score: 2, Tags: []string{"a", "b", "c"}},
+	}
+
+	for _, document := range documents {
+		id, _ := uuid.NewV7()
+		repository.Create(Tests.TestCaseDocumentModel{Id: id, Meta: Repository.NewJSON(document)})
+	}
+}
+
+func Test_json_fields_round_trip_through_create_and_update(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseDocumentModel]()
+	id, _ := uuid.NewV7()
+	meta := Tests.TestCaseDocumentMeta{Address: Tests.TestCaseDocumentAddress{City: "Aarhus"}, Tags: []string{"a"}}
+
+	// Act
+	repository.Create(Tests.TestCaseDocumentModel{Id: id, Meta: Repository.NewJSON(meta)})
+	created := repository.Query().Where("id = ?", id).First()
+
+	meta.Address.City = "Odense"
+	repository.Update(id, map[string]any{"meta": Repository.NewJSON(meta)})
+	updated := repository.Query().Where("id = ?", id).First()
+
+	encoded, _ := json.Marshal(updated)
+
+	// Assert
+	assert.Equal(t, "Aarhus", created.Meta.Data.Address.City)
+	assert.Equal(t, []string{"a"}, created.Meta.Data.Tags)
+	assert.Equal(t, "Odense", updated.Meta.Data.Address.City)
+	assert.Contains(t, string(encoded), `"meta":{"address":{"city":"Odense"`)
+}
+
+func Test_query_builder_can_filter_on_json_values(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseDocumentModel]()
+	seedDocuments(repository)
+
+	// Act
+	danish := repository.Query().WhereJSON("meta->address->country", "=", "DK").Get()
+	scored := repository.Query().WhereJSON("meta->score", ">=", 2).Get()
+	tagged := repository.Query().WhereJSON("meta->tags->2", "=", "c").Get()
+
+	// Assert
+	assert.Equal(t, 2, danish.Count())
+	assert.Equal(t, 2, scored.Count())
+	assert.Equal(t, 1, tagged.Count())
+	assert.Equal(t, "Berlin", tagged.First().Meta.Data.Address.City)
+}
+
+func Test_query_builder_can_filter_on_json_arrays(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseDocumentModel]()
+	seedDocuments(repository)
+
+	// Act
+	containing := repository.Query().WhereJSONContains("meta->tags", "a").Get()
+	containingAll := repository.Query().WhereJSONContains("meta->tags", []string{"a", "c"}).Get()
+	long := repository.Query().WhereJSONLength("meta->tags", ">", 1).Get()
+
+	// Assert
+	assert.Equal(t, 2, containing.Count())
+	assert.Equal(t, 1, containingAll.Count())
+	assert.Equal(t, "Berlin", containingAll.First().Meta.Data.Address.City)
+	assert.Equal(t, 2, long.Count())
+}
+
+func Test_query_builder_can_order_by_json_paths(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseDocumentModel]()
+	seedDocuments(repository)
+
+	// Act
+	byCity := repository.Query().OrderBy("meta->address->city", "asc").Get()
+	byScore := repository.Query().OrderByDesc("meta->score").Get()
+
+	// Assert
+	assert.Equal(t, "Aarhus", byCity.First().Meta.Data.Address.City)
+	assert.Equal(t, "Copenhagen", byCity.Last().Meta.Data.Address.City)
+	assert.Equal(t, 3, byScore.First().Meta.Data.Score)
+	assert.Equal(t, 1, byScore.Last().Meta.Data.Score)
+}
+
+func Test_json_helpers_reject_invalid_paths_and_operators(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseDocumentModel]()
+
+	// Act & Assert
+	assert.Panics(t, func() { repository.Query().WhereJSON("meta->address') OR 1=1 --", "=", "x") })
+	assert.Panics(t, func() { repository.Query().WhereJSON("does_not_exist->city", "=", "x") })
+	assert.Panics(t, func() { repository.Query().WhereJSON("meta->city", "LIKE", "x") })
+	assert.Panics(t, func() { repository.Query().WhereJSONLength("meta->tags", "; DROP", 1) })
+	assert.Panics(t, func() { repository.Query().OrderBy("meta->city'", "asc") })
+}
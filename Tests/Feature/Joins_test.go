@@ -0,0 +1,133 @@
+package Feature
+
+import (
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_query_builder_can_join_tables_using_raw_conditions(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		Join("test_case_relation_models", "test_case_relation_models.test_case_model_id = test_case_models.id").
+		Where("test_case_relation_models.value = ?", "Relation Value [2]").
+		Get()
+
+	// Assert
+	assert.Equal(t, 1, entries.Count())
+	assert.Equal(t, "Value [3]", entries.First().Value)
+}
+
+func Test_query_builder_can_join_tables_using_raw_conditions_with_bindings(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		Join("test_case_relation_models", "test_case_relation_models.test_case_model_id = test_case_models.id AND test_case_relation_models.value = ?", "Relation Value [0]").
+		Get()
+
+	// Assert
+	assert.Equal(t, 1, entries.Count())
+	assert.Equal(t, "Value [1]", entries.First().Value)
+}
+
+func Test_query_builder_can_left_and_right_join_tables(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	Repository.Of[Tests.TestCaseRelationModel]().Query().Where("value", "Relation Value [0]").Delete()
+
+	// Act
+	inner := repository.Query().
+		Join("test_case_relation_models", "test_case_relation_models.test_case_model_id = test_case_models.id").
+		Get()
+
+	left := repository.Query().
+		LeftJoin("test_case_relation_models", "test_case_relation_models.test_case_model_id = test_case_models.id").
+		Get()
+
+	right := Repository.Of[Tests.TestCaseRelationModel]().Query().
+		RightJoin("test_case_models", "test_case_relation_models.test_case_model_id = test_case_models.id").
+		Select("test_case_models.value").
+		Get()
+
+	// Assert
+	assert.Equal(t, 4, inner.Count())
+	assert.Equal(t, 5, left.Count())
+	assert.Equal(t, 5, right.Count())
+}
+
+func Test_query_builder_can_cross_join_tables(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		CrossJoin("test_case_relation_models").
+		Get()
+
+	// Assert
+	assert.Equal(t, 25, entries.Count())
+}
+
+func Test_query_builder_can_join_relationships(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		JoinRelation("TestCaseRelationModels").
+		Where("test_case_relation_models.value IN ?", []string{"Relation Value [1]", "Relation Value [3]"}).
+		OrderBy("test_case_models.value", "asc").
+		Get()
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "Value [2]", entries.First().Value)
+	assert.Equal(t, "Value [4]", entries.Last().Value)
+	assert.Equal(t, 1, len(entries.First().TestCaseRelationModels))
+}
+
+func Test_query_builder_can_left_join_relationships(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	Repository.Of[Tests.TestCaseRelationModel]().Query().Where("value", "Relation Value [0]").Delete()
+
+	// Act
+	entries := repository.Query().
+		LeftJoinRelation("TestCaseRelationModels").
+		Where("test_case_relation_models.id IS NULL").
+		Get()
+
+	// Assert
+	assert.Equal(t, 1, entries.Count())
+	assert.Equal(t, "Value [1]", entries.First().Value)
+}
+
+func Test_query_builder_cannot_join_unknown_relationships(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act & Assert
+	assert.PanicsWithValue(t, "QueryBuilder[JoinRelation]: Model has no relationship named Unknown", func() {
+		repository.Query().JoinRelation("Unknown")
+	})
+}
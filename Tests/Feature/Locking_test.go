@@ -0,0 +1,70 @@
+package Feature
+
+import (
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_query_builder_can_lock_entries_for_update_inside_a_transaction(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	var count int
+
+	// Act
+	err := Repository.Transaction(func(config Repository.Config) error {
+		count = Repository.Of[Tests.TestCaseModel](config).Query().
+			Where("value", "Value [1]").
+			LockForUpdate().
+			Get().
+			Count()
+
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func Test_query_builder_can_apply_shared_locks_skip_locked_and_no_wait_inside_a_transaction(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	var shared, skipped, noWait int
+
+	// Act
+	err := Repository.Transaction(func(config Repository.Config) error {
+		repository := Repository.Of[Tests.TestCaseModel](config)
+
+		shared = repository.Query().SharedLock().Get().Count()
+		skipped = repository.Query().LockForUpdate().SkipLocked().Get().Count()
+		noWait = repository.Query().NoWait().Get().Count()
+
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 5, shared)
+	assert.Equal(t, 5, skipped)
+	assert.Equal(t, 5, noWait)
+}
+
+func Test_query_builder_cannot_lock_entries_outside_a_transaction(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act & Assert
+	assert.PanicsWithValue(t, "QueryBuilder[LockForUpdate]: Locks can only be used inside a transaction", func() {
+		repository.Query().LockForUpdate()
+	})
+
+	assert.PanicsWithValue(t, "QueryBuilder[SkipLocked]: Locks can only be used inside a transaction", func() {
+		repository.Query().SkipLocked()
+	})
+}
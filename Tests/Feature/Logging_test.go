@@ -0,0 +1,216 @@
+package Feature
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/logger"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loggingConfig
+// Copies the default configuration, logging queries as JSON to a buffer
+func loggingConfig(output *bytes.Buffer, configure func(config *Repository.Config)) Repository.Config {
+	config, _ := Repository.Connection(Repository.DefaultConnection)
+	logged := *config
+	logged.Logger = slog.New(slog.NewJSONHandler(output, nil))
+
+	if configure != nil {
+		configure(&logged)
+	}
+
+	return logged
+}
+
+// logRecords
+// Decodes the JSON log records written to a buffer
+func logRecords(output *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var record map[string]any
+		_ = json.Unmarshal([]byte(line), &record)
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func Test_queries_are_logged_to_the_configured_logger(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	var output bytes.Buffer
+	repository := Repository.Of[Tests.TestCaseModel](loggingConfig(&output, nil))
+
+	// Act
+	repository.Query().Where("value = ?", "Value [1]").Get()
+
+	// Assert
+	records := logRecords(&output)
+
+	assert.Len(t, records, 2)
+
+	preload, query := records[0], records[1]
+
+	assert.Equal(t, "INFO", query["level"])
+	assert.Equal(t, "Repository: Query", query["msg"])
+	assert.Equal(t, "SELECT * FROM `test_case_models` WHERE value = \"Value [1]\"", query["sql"])
+	assert.Equal(t, float64(1), query["rows_affected"])
+	assert.Equal(t, "QueryBuilder[Get]", query["caller"])
+	assert.Equal(t, "TestCaseModel", query["model"])
+	assert.Contains(t, query, "duration")
+	assert.Equal(t, "TestCaseRelationModel", preload["model"])
+	assert.Equal(t, "QueryBuilder[Get]", preload["caller"])
+}
+
+func Test_queries_inside_transactions_are_logged_with_their_caller(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	var output bytes.Buffer
+	id, _ := uuid.NewV7()
+
+	// Act
+	err := Repository.Transaction(func(config Repository.Config) error {
+		Repository.Of[Tests.TestCaseVersionedModel](config).Create(Tests.TestCaseVersionedModel{Id: id, Value: "Logged"})
+
+		return nil
+	}, loggingConfig(&output, nil))
+
+	// Assert
+	records := logRecords(&output)
+
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "Repository[Create]", records[0]["caller"])
+	assert.Contains(t, records[0]["sql"], "INSERT INTO `test_case_versioned_models`")
+}
+
+func Test_logging_callbacks_are_registered_once_for_all_transactions(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	var output bytes.Buffer
+	var warnings bytes.Buffer
+	config := loggingConfig(&output, nil)
+	config.DatabaseConnection.Logger = logger.New(log.New(&warnings, "", 0), logger.Config{LogLevel: logger.Warn})
+
+	// Act
+	for transaction := 0; transaction < 3; transaction++ {
+		err := Repository.Transaction(func(transactionConfig Repository.Config) error {
+			Repository.Of[Tests.TestCaseVersionedModel](transactionConfig).All()
+
+			return nil
+		}, config)
+
+		assert.NoError(t, err)
+	}
+
+	// Assert
+	assert.NotContains(t, warnings.String(), "duplicated callback")
+	assert.Len(t, logRecords(&output), 3)
+}
+
+func Test_slow_queries_are_logged_as_warnings(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	var output bytes.Buffer
+	var fallback bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&fallback, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	slow := Repository.Of[Tests.TestCaseVersionedModel](loggingConfig(&output, func(config *Repository.Config) {
+		config.SlowQueryThreshold = time.Nanosecond
+	}))
+
+	withoutLogger := Repository.Of[Tests.TestCaseVersionedModel](loggingConfig(&output, func(config *Repository.Config) {
+		config.Logger = nil
+		config.SlowQueryThreshold = time.Nanosecond
+	}))
+
+	fast := Repository.Of[Tests.TestCaseVersionedModel](loggingConfig(&output, func(config *Repository.Config) {
+		config.Logger = nil
+		config.SlowQueryThreshold = time.Hour
+	}))
+
+	// Act
+	slow.All()
+	withoutLogger.All()
+	fast.All()
+
+	// Assert
+	records := logRecords(&output)
+	fallbackRecords := logRecords(&fallback)
+
+	assert.Len(t, records, 1)
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, "Repository: Slow query", records[0]["msg"])
+	assert.Equal(t, "Repository[All]", records[0]["caller"])
+	assert.Len(t, fallbackRecords, 1)
+	assert.Equal(t, "Repository: Slow query", fallbackRecords[0]["msg"])
+}
+
+func Test_failed_queries_are_logged_as_errors(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	var output bytes.Buffer
+	repository := Repository.Of[Tests.TestCaseModel](loggingConfig(&output, nil))
+
+	// Act
+	_, err := repository.Exec("UPDATE does_not_exist SET value = ?", "Changed")
+	repository.Query().Where("value = ?", "does-not-exist").First()
+
+	// Assert
+	records := logRecords(&output)
+
+	assert.Error(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Equal(t, "Repository[Exec]", records[0]["caller"])
+	assert.Contains(t, records[0]["error"], "no such table")
+	assert.Equal(t, "INFO", records[1]["level"])
+}
+
+func Test_bindings_of_sensitive_columns_are_redacted(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	var output bytes.Buffer
+	repository := Repository.Of[Tests.TestCaseAccountModel](loggingConfig(&output, func(config *Repository.Config) {
+		config.RedactedColumns = []string{"email"}
+	}))
+	id, _ := uuid.NewV7()
+
+	// Act
+	repository.Create(Tests.TestCaseAccountModel{Id: id, Email: "someone@example.com", Password: "secret"})
+	repository.Query().Where("email IN ?", []string{"someone@example.com", "other@example.com"}).Where("password = ?", "secret").Get()
+	repository.Update(id, map[string]any{"password": "changed"})
+
+	// Assert
+	records := logRecords(&output)
+
+	assert.Len(t, records, 3)
+	assert.Contains(t, records[0]["sql"], id.String())
+
+	for _, record := range records {
+		assert.NotContains(t, record["sql"], "example.com")
+		assert.NotContains(t, record["sql"], "secret")
+		assert.NotContains(t, record["sql"], "changed")
+		assert.Contains(t, record["sql"], "[REDACTED]")
+	}
+}
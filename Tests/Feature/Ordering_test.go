@@ -0,0 +1,92 @@
+package Feature
+
+import (
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_query_builder_can_order_by_multiple_columns(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseTreeModel]()
+	seedTree(repository)
+
+	// Act
+	entries := repository.Query().
+		OrderBy("parent_id", "asc").
+		OrderByDesc("Value").
+		Get()
+
+	// Assert
+	assert.Equal(t, 5, entries.Count())
+	assert.Equal(t, "Root", entries.First().Value)
+}
+
+func Test_query_builder_rejects_unknown_columns_and_invalid_directions(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act & Assert
+	assert.Panics(t, func() { repository.Query().OrderBy("value; DROP TABLE test_case_models", "asc") })
+	assert.Panics(t, func() { repository.Query().OrderBy("does_not_exist", "asc") })
+	assert.Panics(t, func() { repository.Query().OrderBy("value", "asc; DROP TABLE test_case_models") })
+	assert.Panics(t, func() { repository.Query().OrderBy("value", "sideways") })
+	assert.Panics(t, func() { repository.Query().OrderBy("value", "asc nulls somewhere") })
+	assert.Equal(t, 5, repository.Query().Get().Count())
+}
+
+func Test_query_builder_can_order_by_latest_and_oldest(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	entries := repository.Query().OrderBy("value", "asc").Get()
+	repository.Update(entries.Get(2).Id, map[string]any{"created_at": entries.First().CreatedAt.AddDate(1, 0, 0)})
+	repository.Update(entries.Get(3).Id, map[string]any{"updated_at": entries.First().CreatedAt.AddDate(-1, 0, 0)})
+
+	// Act
+	latest := repository.Query().Latest().First()
+	oldest := repository.Query().Oldest("updated_at").First()
+
+	// Assert
+	assert.Equal(t, "Value [3]", latest.Value)
+	assert.Equal(t, "Value [4]", oldest.Value)
+}
+
+func Test_query_builder_can_order_nulls_first_and_last(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseTreeModel]()
+	seedTree(repository)
+
+	// Act
+	first := repository.Query().OrderBy("parent_id", "desc nulls first").Get()
+	last := repository.Query().OrderBy("parent_id", "asc nulls last").Get()
+
+	// Assert
+	assert.Equal(t, "Root", first.First().Value)
+	assert.Equal(t, "Root", last.Last().Value)
+}
+
+func Test_query_builder_can_reorder_results(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	cleared := repository.Query().OrderByDesc("value").Reorder().OrderBy("value", "asc").Get()
+	replaced := repository.Query().OrderBy("value", "asc").Reorder("value", "desc").First()
+	random := repository.Query().OrderBy("value", "asc").Reorder().InRandomOrder().Get()
+
+	// Assert
+	assert.Equal(t, "Value [1]", cleared.First().Value)
+	assert.Equal(t, "Value [5]", replaced.Value)
+	assert.Equal(t, 5, random.Count())
+}
@@ -0,0 +1,75 @@
+package Feature
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testCaseValueDto struct {
+	Value string
+	Label string `gorm:"column:label"`
+}
+
+type testCaseRelationCountDto struct {
+	TestCaseModelId uuid.UUID
+	Total           int
+}
+
+func Test_query_builder_can_project_into_another_type(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	projected := Repository.Project[testCaseValueDto](repository.Query().
+		Select("value", "UPPER(value) AS label").
+		OrderBy("value", "desc"))
+
+	// Assert
+	assert.Equal(t, 5, projected.Count())
+	assert.Equal(t, testCaseValueDto{Value: "Value [5]", Label: "VALUE [5]"}, projected.First())
+}
+
+func Test_query_builder_can_project_aggregates(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	modelId := Tests.SeedSharedRelation()
+
+	// Act
+	projected := Repository.Project[testCaseRelationCountDto](repository.Query().
+		Select("test_case_model_id", "COUNT(*) AS total").
+		GroupBy("test_case_model_id").
+		OrderBy("total", "desc"))
+
+	// Assert
+	assert.Equal(t, 4, projected.Count())
+	assert.Equal(t, modelId, projected.First().TestCaseModelId)
+	assert.Equal(t, 2, projected.First().Total)
+}
+
+func Test_query_builder_can_paginate_projections(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	modelId := Tests.SeedSharedRelation()
+
+	// Act
+	paginator := Repository.ProjectPaginate[testCaseRelationCountDto](repository.Query().
+		Select("test_case_model_id", "COUNT(*) AS total").
+		GroupBy("test_case_model_id").
+		OrderBy("total", "desc"), 1, 3, "tests")
+
+	// Assert
+	assert.Equal(t, 4, paginator.Total)
+	assert.Equal(t, 2, paginator.LastPage)
+	assert.Equal(t, 3, paginator.Items.Count())
+	assert.Equal(t, modelId, paginator.Items.First().TestCaseModelId)
+	assert.Equal(t, 2, paginator.Items.First().Total)
+}
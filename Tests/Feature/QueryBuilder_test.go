@@ -1,6 +1,7 @@
 package Feature
 
 import (
+	"github.com/google/uuid"
 	"github.com/nbj/go-repository/Repository"
 	"github.com/nbj/go-repository/Tests"
 	"github.com/stretchr/testify/assert"
@@ -254,3 +255,87 @@ func Test_query_builder_can_delete_entries(t *testing.T) {
 	assert.Equal(t, "Value [2]", collection.First().Value)
 	assert.Equal(t, "Value [4]", collection.Last().Value)
 }
+
+func Test_query_builder_can_select_specific_columns(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entry := repository.Query().
+		Select("id").
+		Where("value", "Value [1]").
+		First()
+
+	// Assert
+	assert.NotEqual(t, uuid.Nil, entry.Id)
+	assert.Equal(t, "", entry.Value)
+}
+
+func Test_query_builder_can_pluck_a_typed_slice_of_values(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	values := Repository.Pluck[string](repository.Query().OrderBy("value", "desc").Take(2), "value")
+
+	// Assert
+	assert.Equal(t, []string{"Value [5]", "Value [4]"}, values)
+}
+
+func Test_query_builder_can_select_distinct_values(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	Tests.SeedSharedRelation()
+
+	// Act
+	all := Repository.Pluck[uuid.UUID](repository.Query(), "test_case_model_id")
+	distinct := Repository.Pluck[uuid.UUID](repository.Query().Distinct(), "test_case_model_id")
+
+	// Assert
+	assert.Equal(t, 5, len(all))
+	assert.Equal(t, 4, len(distinct))
+}
+
+func Test_query_builder_can_group_and_filter_groups(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	modelId := Tests.SeedSharedRelation()
+
+	// Act
+	shared := Repository.Pluck[uuid.UUID](repository.Query().
+		GroupBy("test_case_model_id").
+		Having("COUNT(*) > ?", 1), "test_case_model_id")
+
+	groups := Repository.Pluck[int64](repository.Query().
+		Select("COUNT(*)").
+		GroupBy("test_case_model_id"), "COUNT(*)")
+
+	// Assert
+	assert.Equal(t, []uuid.UUID{modelId}, shared)
+	assert.Equal(t, 4, len(groups))
+}
+
+func Test_query_builder_can_get_a_single_value(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	value := Repository.Value[string](repository.Query().Where("value", "Value [3]"), "value")
+	count := Repository.Value[int64](repository.Query(), "COUNT(*)")
+	missing := Repository.Value[string](repository.Query().Where("value", "this-does-not-exist"), "value")
+
+	// Assert
+	assert.Equal(t, "Value [3]", value)
+	assert.Equal(t, int64(5), count)
+	assert.Equal(t, "", missing)
+}
@@ -0,0 +1,257 @@
+package Feature
+
+import (
+	"errors"
+	"github.com/nbj/go-repository/Queue"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testCasePayload struct {
+	Value string `json:"value"`
+}
+
+func Test_payloads_can_be_enqueued_and_claimed(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default")
+	queue.Enqueue(testCasePayload{Value: "Value [1]"})
+	queue.Enqueue(testCasePayload{Value: "Value [2]"})
+
+	// Act
+	reservationA, errA := queue.Claim()
+	reservationB, errB := queue.Claim()
+	reservationC, errC := queue.Claim()
+
+	// Assert
+	assert.Nil(t, errA)
+	assert.Nil(t, errB)
+	assert.Nil(t, errC)
+	assert.Equal(t, "Value [1]", reservationA.Payload.Value)
+	assert.Equal(t, "Value [2]", reservationB.Payload.Value)
+	assert.Nil(t, reservationC)
+	assert.Equal(t, Queue.StatusReserved, reservationA.Job.Status)
+	assert.Equal(t, 1, reservationA.Job.Attempts)
+	assert.Equal(t, 2, queue.Count(Queue.StatusReserved))
+}
+
+func Test_queues_only_claim_their_own_jobs(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	mail := Queue.Of[testCasePayload]("mail")
+	reports := Queue.Of[testCasePayload]("reports")
+	mail.Enqueue(testCasePayload{Value: "Value [MAIL]"})
+
+	// Act
+	reservation, err := reports.Claim()
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, reservation)
+	assert.Equal(t, 1, mail.Count(Queue.StatusPending))
+}
+
+func Test_delayed_jobs_are_not_claimed_before_they_are_available(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default")
+	queue.Later(time.Hour, testCasePayload{Value: "Value [LATER]"})
+
+	// Act
+	reservation, err := queue.Claim()
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Nil(t, reservation)
+}
+
+func Test_a_worker_completes_jobs_handled_successfully(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default")
+	queue.Enqueue(testCasePayload{Value: "Value [1]"})
+
+	var handled string
+
+	// Act
+	worked, err := queue.Work(func(payload testCasePayload) error {
+		handled = payload.Value
+
+		return nil
+	})
+
+	// Assert
+	assert.True(t, worked)
+	assert.Nil(t, err)
+	assert.Equal(t, "Value [1]", handled)
+	assert.Equal(t, 1, queue.Count(Queue.StatusCompleted))
+}
+
+func Test_a_worker_returns_false_when_no_jobs_are_available(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default")
+
+	// Act
+	worked, err := queue.Work(func(payload testCasePayload) error {
+		return nil
+	})
+
+	// Assert
+	assert.False(t, worked)
+	assert.Nil(t, err)
+}
+
+func Test_failed_jobs_are_retried_after_a_backoff(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default", Queue.Options{
+		Backoff: Queue.ExponentialBackoff(time.Hour),
+	})
+
+	job := queue.Enqueue(testCasePayload{Value: "Value [1]"})
+
+	// Act
+	worked, err := queue.Work(func(payload testCasePayload) error {
+		return errors.New("something-went-wrong")
+	})
+
+	reservation, _ := queue.Claim()
+
+	// Assert
+	assert.True(t, worked)
+	assert.Nil(t, err)
+	assert.Nil(t, reservation)
+
+	failed := queue.Find(job.Id)
+	assert.Equal(t, Queue.StatusPending, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, "something-went-wrong", failed.LastError)
+	assert.True(t, failed.AvailableAt.After(time.Now().Add(59*time.Minute)))
+}
+
+func Test_jobs_are_dead_lettered_when_out_of_attempts(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default", Queue.Options{
+		MaxAttempts: 2,
+		Backoff: func(attempts int) time.Duration {
+			return 0
+		},
+	})
+
+	job := queue.Enqueue(testCasePayload{Value: "Value [1]"})
+
+	failing := func(payload testCasePayload) error {
+		panic("something-went-wrong")
+	}
+
+	// Act
+	queue.Work(failing)
+	queue.Work(failing)
+	worked, _ := queue.Work(failing)
+
+	// Assert
+	assert.False(t, worked)
+	assert.Equal(t, 1, queue.Dead().Count())
+	assert.Equal(t, job.Id, queue.Dead().First().Id)
+	assert.Equal(t, 2, queue.Dead().First().Attempts)
+	assert.Equal(t, "Queue[Work]: Handler panicked: something-went-wrong", queue.Dead().First().LastError)
+}
+
+func Test_dead_jobs_can_be_retried(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default", Queue.Options{MaxAttempts: 1})
+	job := queue.Enqueue(testCasePayload{Value: "Value [1]"})
+
+	queue.Work(func(payload testCasePayload) error {
+		return errors.New("something-went-wrong")
+	})
+
+	// Act
+	err := queue.Retry(job.Id)
+	reservation, _ := queue.Claim()
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 0, queue.Dead().Count())
+	assert.Equal(t, "Value [1]", reservation.Payload.Value)
+	assert.NotNil(t, queue.Retry(job.Id))
+}
+
+func Test_abandoned_jobs_are_claimed_again_after_the_visibility_timeout(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default", Queue.Options{
+		VisibilityTimeout: 10 * time.Millisecond,
+	})
+
+	queue.Enqueue(testCasePayload{Value: "Value [1]"})
+	abandoned, _ := queue.Claim()
+
+	// Act
+	invisible, _ := queue.Claim()
+	time.Sleep(20 * time.Millisecond)
+	reclaimed, _ := queue.Claim()
+
+	// Assert
+	assert.Nil(t, invisible)
+	assert.Equal(t, abandoned.Job.Id, reclaimed.Job.Id)
+	assert.Equal(t, 2, reclaimed.Job.Attempts)
+	assert.True(t, errors.Is(queue.Complete(abandoned.Job), Repository.ErrStaleModel))
+	assert.Nil(t, queue.Complete(reclaimed.Job))
+}
+
+func Test_jobs_abandoned_on_their_final_attempt_are_dead_lettered(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default", Queue.Options{
+		VisibilityTimeout: 10 * time.Millisecond,
+		MaxAttempts:       1,
+	})
+
+	queue.Enqueue(testCasePayload{Value: "Value [1]"})
+	queue.Claim()
+	time.Sleep(20 * time.Millisecond)
+
+	// Act
+	reservation, _ := queue.Claim()
+
+	// Assert
+	assert.Nil(t, reservation)
+	assert.Equal(t, 1, queue.Dead().Count())
+	assert.Equal(t, "Visibility timeout exceeded", queue.Dead().First().LastError)
+}
+
+func Test_jobs_can_be_purged_by_status(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	queue := Queue.Of[testCasePayload]("default")
+	queue.Enqueue(testCasePayload{Value: "Value [1]"})
+	queue.Enqueue(testCasePayload{Value: "Value [2]"})
+	queue.Work(func(payload testCasePayload) error {
+		return nil
+	})
+
+	// Act
+	queue.Purge(Queue.StatusCompleted)
+
+	// Assert
+	assert.Equal(t, 0, queue.Count(Queue.StatusCompleted))
+	assert.Equal(t, 1, queue.Count(Queue.StatusPending))
+}
@@ -0,0 +1,152 @@
+package Feature
+
+import (
+	"database/sql"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testCaseRawSelectModel struct {
+	Value  string
+	Suffix string
+}
+
+func Test_repository_can_query_raw_sql(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	positional := repository.Raw("SELECT * FROM test_case_models WHERE value IN ? ORDER BY value", []string{"Value [1]", "Value [2]"})
+	named := repository.Raw("SELECT * FROM test_case_models WHERE value = @value OR value = @other", map[string]any{
+		"value": "Value [3]",
+		"other": "Value [4]",
+	})
+	namedArguments := repository.Raw("SELECT * FROM test_case_models WHERE value = @value", sql.Named("value", "Value [5]"))
+
+	// Assert
+	assert.Equal(t, 2, positional.Count())
+	assert.Equal(t, "Value [1]", positional.First().Value)
+	assert.Len(t, positional.First().TestCaseRelationModels, 1)
+	assert.Equal(t, "Relation Value [0]", positional.First().TestCaseRelationModels[0].Value)
+	assert.Equal(t, 2, named.Count())
+	assert.Equal(t, "Value [5]", namedArguments.First().Value)
+}
+
+func Test_repository_can_execute_raw_sql(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	affected, err := repository.Exec("UPDATE test_case_models SET value = @value WHERE value IN @values", map[string]any{
+		"value":  "Changed",
+		"values": []string{"Value [1]", "Value [2]"},
+	})
+	_, failure := repository.Exec("UPDATE does_not_exist SET value = ?", "Changed")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+	assert.Equal(t, 2, repository.Query().Where("value = ?", "Changed").Get().Count())
+	assert.Error(t, failure)
+	assert.Equal(t, failure, repository.GetLatestError())
+}
+
+func Test_query_builder_can_filter_and_order_using_raw_sql(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		WhereRaw("value != @excluded", map[string]any{"excluded": "Value [1]"}).
+		OrderByRaw("CASE WHEN value = ? THEN 0 ELSE 1 END", "Value [4]").
+		OrderByDesc("value").
+		Get()
+
+	first := repository.Query().
+		OrderByRaw("CASE WHEN value = @value THEN 0 ELSE 1 END", sql.Named("value", "Value [3]")).
+		First()
+
+	reordered := repository.Query().
+		OrderByRaw("CASE WHEN value = ? THEN 0 ELSE 1 END", "Value [3]").
+		Reorder("value", "asc").
+		First()
+
+	// Assert
+	assert.Equal(t, 4, entries.Count())
+	assert.Equal(t, "Value [4]", entries.First().Value)
+	assert.Equal(t, "Value [5]", entries.Get(1).Value)
+	assert.Equal(t, "Value [3]", first.Value)
+	assert.Equal(t, "Value [1]", reordered.Value)
+}
+
+func Test_query_builder_can_order_unions_using_raw_sql(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		Where("value = ?", "Value [1]").
+		Union(repository.Query().Where("value IN ?", []string{"Value [2]", "Value [4]"})).
+		OrderByRaw("CASE WHEN value = @first THEN 0 ELSE 1 END", map[string]any{"first": "Value [2]"}).
+		OrderByRaw("value DESC").
+		Get()
+
+	first := repository.Query().
+		Where("value = ?", "Value [1]").
+		Union(repository.Query().Where("value = ?", "Value [3]")).
+		OrderByRaw("value DESC").
+		First()
+
+	// Assert
+	assert.Equal(t, 3, entries.Count())
+	assert.Equal(t, "Value [2]", entries.Get(0).Value)
+	assert.Equal(t, "Value [4]", entries.Get(1).Value)
+	assert.Equal(t, "Value [1]", entries.Get(2).Value)
+	assert.Equal(t, "Value [3]", first.Value)
+}
+
+func Test_query_builder_can_select_and_filter_groups_using_raw_sql(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	modelId := Tests.SeedSharedRelation()
+
+	// Act
+	selected := Repository.Project[testCaseRawSelectModel](repository.Query().
+		Select("value").
+		SelectRaw("substr(value, @start) AS suffix", map[string]any{"start": 16}).
+		OrderBy("value", "asc"))
+
+	grouped := Repository.Project[testCaseRelationCountDto](repository.Query().
+		Select("test_case_model_id", "COUNT(*) AS total").
+		GroupBy("test_case_model_id").
+		HavingRaw("COUNT(*) > ?", 1))
+
+	// Assert
+	assert.Equal(t, "[0]", selected.First().Suffix)
+	assert.Equal(t, 1, grouped.Count())
+	assert.Equal(t, modelId, grouped.First().TestCaseModelId)
+	assert.Equal(t, 2, grouped.First().Total)
+}
+
+func Test_raw_sql_requires_all_named_bindings(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act & Assert
+	assert.Panics(t, func() { repository.Query().WhereRaw("value = @value", map[string]any{"other": 1}) })
+	assert.Panics(t, func() { repository.Raw("SELECT * FROM does_not_exist") })
+}
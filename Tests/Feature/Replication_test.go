@@ -0,0 +1,149 @@
+package Feature
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func Test_repository_reads_go_to_read_connections(t *testing.T) {
+	// Arrange
+	Tests.SetupReplicatedEnvironment(1)
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	all := repository.All()
+	first := repository.First()
+	queried := repository.GormQuery(func(query *gorm.DB) *gorm.DB {
+		return query.Where("value = ?", "Value [2]")
+	})
+	built := repository.Query().Where("value", "Value [3]").Get()
+
+	// Assert
+	assert.Equal(t, 5, all.Count())
+	assert.Equal(t, "Value [1]", first.Value)
+	assert.Equal(t, 1, queried.Count())
+	assert.Equal(t, 1, built.Count())
+}
+
+func Test_repository_writes_go_to_the_primary_connection(t *testing.T) {
+	// Arrange
+	writer, _ := Tests.SetupReplicatedEnvironment(1)
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	id, _ := uuid.NewV7()
+
+	// Act
+	repository.Create(Tests.TestCaseModel{Id: id, Value: "Value [NEW]"})
+	err := repository.Update(id, map[string]any{"value": "Value [UPDATED]"})
+
+	// Assert
+	var entries []Tests.TestCaseModel
+	writer.Find(&entries)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "Value [UPDATED]", entries[0].Value)
+	assert.Equal(t, 5, repository.All().Count())
+}
+
+func Test_query_builder_deletes_go_to_the_primary_connection(t *testing.T) {
+	// Arrange
+	writer, readers := Tests.SetupReplicatedEnvironment(1)
+	Tests.SeedTestData(writer)
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	repository.Query().Where("value", "Value [1]").Delete()
+
+	// Assert
+	var writerCount, readerCount int64
+	writer.Model(&Tests.TestCaseModel{}).Count(&writerCount)
+	readers[0].Model(&Tests.TestCaseModel{}).Count(&readerCount)
+
+	assert.Equal(t, int64(4), writerCount)
+	assert.Equal(t, int64(5), readerCount)
+}
+
+func Test_reads_are_spread_round_robin_across_read_connections(t *testing.T) {
+	// Arrange
+	_, readers := Tests.SetupReplicatedEnvironment(2, Repository.RoundRobin())
+	readers[1].Where("value = ?", "Value [1]").Delete(&Tests.TestCaseModel{})
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	countA := repository.All().Count()
+	countB := repository.All().Count()
+	countC := repository.Query().Get().Count()
+
+	// Assert
+	assert.Equal(t, 5, countA)
+	assert.Equal(t, 4, countB)
+	assert.Equal(t, 5, countC)
+}
+
+func Test_reads_can_use_a_custom_read_policy(t *testing.T) {
+	// Arrange
+	_, readers := Tests.SetupReplicatedEnvironment(2, func(readers []*gorm.DB) *gorm.DB {
+		return readers[1]
+	})
+	readers[1].Where("value = ?", "Value [1]").Delete(&Tests.TestCaseModel{})
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	countA := repository.All().Count()
+	countB := repository.All().Count()
+
+	// Assert
+	assert.Equal(t, 4, countA)
+	assert.Equal(t, 4, countB)
+}
+
+func Test_reads_can_be_pinned_to_the_primary_connection(t *testing.T) {
+	// Arrange
+	Tests.SetupReplicatedEnvironment(1)
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	id, _ := uuid.NewV7()
+	repository.Create(Tests.TestCaseModel{Id: id, Value: "Value [NEW]"})
+
+	// Act
+	built := repository.Query().Where("value", "Value [NEW]").UseWriter().Get()
+	replicated := repository.Query().Where("value", "Value [NEW]").Get()
+	all := repository.UseWriter().All()
+
+	// Assert
+	assert.Equal(t, 1, built.Count())
+	assert.Equal(t, 0, replicated.Count())
+	assert.Equal(t, 1, all.Count())
+	assert.Equal(t, "Value [NEW]", repository.First().Value)
+}
+
+func Test_transactions_are_pinned_to_the_primary_connection(t *testing.T) {
+	// Arrange
+	Tests.SetupReplicatedEnvironment(1)
+
+	var count int
+
+	// Act
+	err := Repository.Transaction(func(config Repository.Config) error {
+		transaction := Repository.Of[Tests.TestCaseModel](config)
+
+		id, _ := uuid.NewV7()
+		transaction.Create(Tests.TestCaseModel{Id: id, Value: "Value [NEW]"})
+		count = transaction.All().Count()
+
+		return nil
+	})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+}
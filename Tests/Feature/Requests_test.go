@@ -0,0 +1,151 @@
+package Feature
+
+import (
+	"errors"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"net/url"
+	"testing"
+)
+
+func requestAllowList() Repository.AllowList {
+	return Repository.AllowList{
+		Filters: map[string]Repository.Filter{
+			"value":   Repository.ExactFilter("value"),
+			"search":  Repository.PartialFilter("value"),
+			"created": Repository.RangeFilter("created_at"),
+			"ending_in": Repository.ScopeFilter(func(query *gorm.DB, values []string) *gorm.DB {
+				return query.Where("value LIKE ?", "%"+values[0]+"]")
+			}),
+		},
+		Sorts:       []string{"value", "created_at"},
+		Includes:    []string{"TestCaseModel"},
+		DefaultSort: "value",
+		MaxPerPage:  3,
+	}
+}
+
+func Test_query_builder_can_filter_from_a_request(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	exact, _ := url.ParseQuery("filter[value]=Relation Value [1],Relation Value [3]")
+	partial, _ := url.ParseQuery("filter[search]=value [2")
+	wildcard := url.Values{"filter[search]": {"%"}}
+	scoped, _ := url.ParseQuery("filter[ending_in]=4")
+
+	// Act
+	exactQuery, exactErr := repository.Query().FromRequest(exact, requestAllowList())
+	partialQuery, _ := repository.Query().FromRequest(partial, requestAllowList())
+	wildcardQuery, _ := repository.Query().FromRequest(wildcard, requestAllowList())
+	scopedQuery, _ := repository.Query().FromRequest(scoped, requestAllowList())
+
+	// Assert
+	assert.NoError(t, exactErr)
+	assert.Equal(t, 2, exactQuery.Get().Count())
+	assert.Equal(t, "Relation Value [1]", exactQuery.Get().First().Value)
+	assert.Equal(t, "Relation Value [2]", partialQuery.First().Value)
+	assert.Equal(t, 0, wildcardQuery.Get().Count())
+	assert.Equal(t, "Relation Value [4]", scopedQuery.First().Value)
+}
+
+func Test_query_builder_can_filter_ranges_from_a_request(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	entries := repository.Query().OrderBy("value", "asc").Get()
+	values := url.Values{}
+	values.Set("filter[created][from]", entries.Get(1).CreatedAt.Format("2006-01-02 15:04:05.999999999-07:00"))
+	values.Set("filter[created][to]", entries.Get(3).CreatedAt.Format("2006-01-02 15:04:05.999999999-07:00"))
+
+	// Act
+	query, err := repository.Query().FromRequest(values, requestAllowList())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, query.Get().Count())
+	assert.Equal(t, "Relation Value [1]", query.Get().First().Value)
+}
+
+func Test_query_builder_can_sort_include_and_paginate_from_a_request(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	values, _ := url.ParseQuery("sort=-value&include=TestCaseModel&page=2&per_page=100")
+
+	// Act
+	query, err := repository.Query().FromRequest(values, requestAllowList())
+	paginator := query.PaginateRequest("/relations")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, paginator.Page)
+	assert.Equal(t, 3, paginator.PerPage)
+	assert.Equal(t, 2, paginator.Items.Count())
+	assert.Equal(t, "Relation Value [1]", paginator.Items.First().Value)
+	assert.Equal(t, "Value [2]", paginator.Items.First().TestCaseModel.Value)
+}
+
+func Test_query_builder_paginates_requests_without_per_page_by_the_maximum(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+
+	// Act
+	query, err := repository.Query().FromRequest(url.Values{}, Repository.AllowList{MaxPerPage: 2})
+	paginator := query.PaginateRequest("/relations")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, paginator.Page)
+	assert.Equal(t, 2, paginator.PerPage)
+	assert.Equal(t, 2, paginator.Items.Count())
+	assert.Equal(t, 3, paginator.LastPage)
+}
+
+func Test_query_builder_uses_the_default_sort_for_requests(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+
+	// Act
+	query, err := repository.Query().FromRequest(url.Values{}, requestAllowList())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Relation Value [0]", query.First().Value)
+	assert.Nil(t, query.First().TestCaseModel)
+}
+
+func Test_query_builder_rejects_requests_outside_the_allow_list(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseRelationModel]()
+	requests := []url.Values{
+		{"filter[id]": {"1"}},
+		{"filter[value][from]": {"1"}},
+		{"filter[created]": {"1"}},
+		{"filter[value]]": {"1"}},
+		{"sort": {"-updated_at"}},
+		{"sort": {"value;DROP TABLE test_case_relation_models"}},
+		{"include": {"TestCaseModel.TestCaseRelationModels"}},
+		{"page": {"0"}},
+		{"per_page": {"many"}},
+	}
+
+	for _, values := range requests {
+		// Act
+		_, err := repository.Query().FromRequest(values, requestAllowList())
+
+		// Assert
+		assert.True(t, errors.Is(err, Repository.ErrInvalidRequest), values.Encode())
+	}
+}
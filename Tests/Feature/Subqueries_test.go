@@ -0,0 +1,127 @@
+package Feature
+
+import (
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testCaseRelationCountedModel struct {
+	Tests.TestCaseModel
+	RelationCount int
+}
+
+func Test_query_builder_can_filter_using_where_in_with_a_subquery(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	relations := Repository.Of[Tests.TestCaseRelationModel]().Query().
+		Select("test_case_model_id").
+		Where("value IN ?", []string{"Relation Value [0]", "Relation Value [4]"})
+
+	// Act
+	entries := repository.Query().
+		WhereIn("id", relations).
+		Get()
+
+	excluded := repository.Query().
+		WhereNotIn("id", relations).
+		Get()
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "Value [1]", entries.First().Value)
+	assert.Equal(t, "Value [5]", entries.Last().Value)
+	assert.Equal(t, 3, excluded.Count())
+}
+
+func Test_query_builder_can_filter_using_where_in_with_a_slice(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		WhereIn("value", []string{"Value [2]", "Value [3]"}).
+		Get()
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+}
+
+func Test_query_builder_can_filter_using_correlated_exists_subqueries(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+	Repository.Of[Tests.TestCaseRelationModel]().Query().Where("value", "Relation Value [0]").Delete()
+
+	relations := func() *Repository.QueryBuilder[Tests.TestCaseRelationModel] {
+		return Repository.Of[Tests.TestCaseRelationModel]().Query().
+			WhereColumn("test_case_relation_models.test_case_model_id", "=", "test_case_models.id")
+	}
+
+	// Act
+	with := repository.Query().WhereExists(relations()).Get()
+	without := repository.Query().WhereNotExists(relations()).Get()
+
+	// Assert
+	assert.Equal(t, 4, with.Count())
+	assert.Equal(t, 1, without.Count())
+	assert.Equal(t, "Value [1]", without.First().Value)
+}
+
+func Test_query_builder_rejects_invalid_column_comparison_operators(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act & Assert
+	assert.Panics(t, func() {
+		repository.Query().WhereColumn("id", "= id; DROP TABLE test_case_models; --", "id")
+	})
+}
+
+func Test_query_builder_can_select_subqueries(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	count := Repository.Of[Tests.TestCaseRelationModel]().Query().
+		Select("COUNT(*)").
+		WhereColumn("test_case_relation_models.test_case_model_id", "=", "test_case_models.id")
+
+	// Act
+	entries := Repository.Project[testCaseRelationCountedModel](Repository.Of[Tests.TestCaseModel]().Query().
+		SelectSub(count, "relation_count").
+		OrderBy("value", "asc"))
+
+	// Assert
+	assert.Equal(t, 5, entries.Count())
+	assert.Equal(t, "Value [1]", entries.First().Value)
+	assert.Equal(t, 1, entries.First().RelationCount)
+}
+
+func Test_query_builder_can_select_from_subqueries(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	recent := Repository.Of[Tests.TestCaseModel]().Query().
+		OrderBy("value", "desc").
+		Take(3)
+
+	// Act
+	entries := Repository.Of[Tests.TestCaseModel]().Query().
+		FromSub(recent, "recent").
+		Where("recent.value <> ?", "Value [4]").
+		OrderBy("value", "asc").
+		Get()
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "Value [3]", entries.First().Value)
+	assert.Equal(t, "Value [5]", entries.Last().Value)
+}
@@ -0,0 +1,284 @@
+package Feature
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Telemetry"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"sync"
+	"testing"
+)
+
+// telemetryConfig
+// Copies the default configuration, instrumenting it with in-memory exporters
+func telemetryConfig() (Repository.Config, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	spans := tracetest.NewSpanRecorder()
+	metrics := sdkmetric.NewManualReader()
+
+	config, _ := Repository.Connection(Repository.DefaultConnection)
+	instrumented := *config
+	instrumented.Instrumentation = Telemetry.Of(Telemetry.Options{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(metrics)),
+	})
+
+	return instrumented, spans, metrics
+}
+
+// collectMetric
+// Collects the data points of a metric recorded by the reader
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Aggregation {
+	var collected metricdata.ResourceMetrics
+
+	assert.NoError(t, reader.Collect(context.Background(), &collected))
+
+	for _, scope := range collected.ScopeMetrics {
+		for _, recorded := range scope.Metrics {
+			if recorded.Name == name {
+				return recorded.Data
+			}
+		}
+	}
+
+	return nil
+}
+
+// recordQuerySpans
+// Records the span each query of a connection runs in, as a
+// database-level instrumentation creating child spans would see it
+func recordQuerySpans(config Repository.Config) *[]trace.SpanContext {
+	var recorded []trace.SpanContext
+	record := func(query *gorm.DB) {
+		recorded = append(recorded, trace.SpanContextFromContext(query.Statement.Context))
+	}
+
+	_ = config.DatabaseConnection.Callback().Query().Before("*").Register("test:record_query_span", record)
+	_ = config.DatabaseConnection.Callback().Create().Before("*").Register("test:record_create_span", record)
+
+	return &recorded
+}
+
+// spanAttribute
+// Gets the value of an attribute of a span
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, value := range span.Attributes() {
+		if value.Key == key {
+			return value.Value.AsString()
+		}
+	}
+
+	return ""
+}
+
+func Test_terminal_calls_emit_spans(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config, spans, _ := telemetryConfig()
+	repository := Repository.Of[Tests.TestCaseModel](config)
+
+	// Act
+	repository.Query().Where("value = ?", "Value [1]").Get()
+	repository.All()
+
+	// Assert
+	ended := spans.Ended()
+	assert.Len(t, ended, 2)
+	assert.Equal(t, "QueryBuilder[Get]", ended[0].Name())
+	assert.Equal(t, "Repository[All]", ended[1].Name())
+	assert.Equal(t, "QueryBuilder[Get]", spanAttribute(ended[0], "repository.method"))
+	assert.Equal(t, "TestCaseModel", spanAttribute(ended[0], "repository.model"))
+	assert.Equal(t, codes.Unset, ended[0].Status().Code)
+}
+
+func Test_queries_of_terminal_calls_run_in_the_context_of_their_span(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config, spans, _ := telemetryConfig()
+	queries := recordQuerySpans(config)
+	repository := Repository.Of[Tests.TestCaseVersionedModel](config)
+
+	// Act
+	repository.Query().Where("value = ?", "Value [1]").Get()
+	repository.All()
+	repository.Query().Exists()
+
+	// Assert
+	ended := spans.Ended()
+	assert.Len(t, ended, 3)
+	assert.Len(t, *queries, 3)
+
+	for index, span := range ended {
+		assert.Equal(t, span.SpanContext(), (*queries)[index])
+	}
+}
+
+func Test_concurrent_terminal_calls_run_in_the_context_of_their_own_span(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config, spans, _ := telemetryConfig()
+	repository := Repository.Of[Tests.TestCaseVersionedModel](config)
+
+	// The in-memory database only lives on a single connection
+	connection, _ := config.DatabaseConnection.DB()
+	connection.SetMaxOpenConns(1)
+
+	var mutex sync.Mutex
+	queries := map[trace.SpanID]int{}
+
+	_ = config.DatabaseConnection.Callback().Query().Before("*").Register("test:record_concurrent_span", func(query *gorm.DB) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		queries[trace.SpanContextFromContext(query.Statement.Context).SpanID()]++
+	})
+
+	var group sync.WaitGroup
+
+	// Act
+	for index := 0; index < 20; index++ {
+		group.Add(1)
+
+		go func() {
+			defer group.Done()
+
+			repository.All()
+		}()
+	}
+
+	group.Wait()
+
+	// Assert
+	ended := spans.Ended()
+	assert.Len(t, ended, 20)
+
+	for _, span := range ended {
+		assert.Equal(t, "Repository[All]", span.Name())
+		assert.Equal(t, 1, queries[span.SpanContext().SpanID()])
+	}
+}
+
+func Test_terminal_calls_record_counts_and_latencies_by_method_and_model(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config, _, metrics := telemetryConfig()
+	repository := Repository.Of[Tests.TestCaseModel](config)
+
+	// Act
+	repository.Query().Get()
+	repository.Query().Get()
+	repository.Query().Exists()
+
+	// Assert
+	operations := collectMetric(t, metrics, "repository.operations").(metricdata.Sum[int64])
+	counts := map[string]int64{}
+
+	for _, point := range operations.DataPoints {
+		method, _ := point.Attributes.Value("repository.method")
+		model, _ := point.Attributes.Value("repository.model")
+		assert.Equal(t, "TestCaseModel", model.AsString())
+		counts[method.AsString()] = point.Value
+	}
+
+	assert.Equal(t, map[string]int64{"QueryBuilder[Get]": 2, "QueryBuilder[Exists]": 1}, counts)
+
+	duration := collectMetric(t, metrics, "repository.operation.duration").(metricdata.Histogram[float64])
+	var measured uint64
+
+	for _, point := range duration.DataPoints {
+		measured += point.Count
+	}
+
+	assert.Equal(t, uint64(3), measured)
+}
+
+func Test_failed_terminal_calls_are_recorded_as_errors(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config, spans, metrics := telemetryConfig()
+	repository := Repository.Of[Tests.TestCaseModel](config)
+
+	// Act
+	assert.PanicsWithValue(t, "QueryBuilder[FirstOrFail]: Model not found!", func() {
+		repository.Query().Where("value = ?", "Unknown").FirstOrFail()
+	})
+
+	err := repository.Update(uuid.New(), map[string]any{"value": "Updated"})
+
+	// Assert
+	assert.Error(t, err)
+
+	ended := spans.Ended()
+	assert.Len(t, ended, 2)
+	assert.Equal(t, "QueryBuilder[FirstOrFail]", ended[0].Name())
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	assert.Equal(t, "QueryBuilder[FirstOrFail]: Model not found!", ended[0].Status().Description)
+	assert.Equal(t, "Repository[Update]", ended[1].Name())
+	assert.Equal(t, codes.Error, ended[1].Status().Code)
+
+	failures := collectMetric(t, metrics, "repository.operation.errors").(metricdata.Sum[int64])
+	var failed int64
+
+	for _, point := range failures.DataPoints {
+		failed += point.Value
+	}
+
+	assert.Equal(t, int64(2), failed)
+}
+
+func Test_operations_inside_transactions_are_children_of_the_transaction_span(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config, spans, _ := telemetryConfig()
+
+	queries := recordQuerySpans(config)
+
+	// Act
+	err := Repository.Transaction(func(transactionConfig Repository.Config) error {
+		Repository.Of[Tests.TestCaseModel](transactionConfig).Create(Tests.TestCaseModel{Value: "Value [6]"})
+
+		return nil
+	}, config)
+
+	// Assert
+	assert.NoError(t, err)
+
+	ended := spans.Ended()
+	assert.Len(t, ended, 2)
+	assert.Equal(t, "Repository[Create]", ended[0].Name())
+	assert.Equal(t, "Transaction", ended[1].Name())
+	assert.Equal(t, ended[1].SpanContext().SpanID(), ended[0].Parent().SpanID())
+	assert.Equal(t, []trace.SpanContext{ended[0].SpanContext()}, *queries)
+	assert.Equal(t, "", spanAttribute(ended[1], "repository.model"))
+}
+
+func Test_repositories_are_not_instrumented_by_default(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	instrumentation := Repository.NoopInstrumentation{}
+	ctx := context.Background()
+
+	// Act
+	started, finish := instrumentation.Start(ctx, Repository.Operation{Method: "QueryBuilder[Get]"})
+	finish(nil)
+
+	// Assert
+	assert.Equal(t, ctx, started)
+	assert.Equal(t, 5, Repository.Of[Tests.TestCaseModel]().Query().Get().Count())
+}
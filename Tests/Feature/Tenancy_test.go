@@ -0,0 +1,328 @@
+package Feature
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+)
+
+func tenantConfig(tenant any, strategy ...Repository.TenancyStrategy) Repository.Config {
+	config, _ := Repository.Connection(Repository.DefaultConnection)
+
+	scoped := *config
+	scoped.Tenant = tenant
+
+	if len(strategy) > 0 {
+		scoped.TenancyStrategy = strategy[0]
+	}
+
+	return scoped
+}
+
+// migrateTenantTables
+// Creates the prefixed tables of models and their relations for tenants
+func migrateTenantTables(config Repository.Config, tenants ...string) {
+	for _, tenant := range tenants {
+		config.DatabaseConnection.Table(tenant + "_test_case_models").AutoMigrate(&Tests.TestCaseModel{})
+		config.DatabaseConnection.Table(tenant + "_test_case_relation_models").AutoMigrate(&Tests.TestCaseRelationModel{})
+	}
+}
+
+// attachTenantSchema
+// Creates the tables of a tenant in a schema of its own. SQLite knows
+// attached databases as schemas, which live on a single connection
+func attachTenantSchema(config Repository.Config, tenant string) {
+	connection, _ := config.DatabaseConnection.DB()
+	connection.SetMaxOpenConns(1)
+
+	config.DatabaseConnection.Exec("ATTACH DATABASE ':memory:' AS " + tenant)
+
+	for _, table := range []string{"test_case_models", "test_case_relation_models"} {
+		var definition string
+		config.DatabaseConnection.Raw("SELECT sql FROM sqlite_master WHERE name = ?", table).Scan(&definition)
+		config.DatabaseConnection.Exec(strings.Replace(definition, "`"+table+"`", "`"+tenant+"`.`"+table+"`", 1))
+	}
+}
+
+func seedTenants() {
+	for _, tenant := range []string{"tenant-a", "tenant-b"} {
+		repository := Repository.Of[Tests.TestCaseTenantModel](tenantConfig(tenant))
+
+		for _, value := range []string{"Value [1]", "Value [2]"} {
+			id, _ := uuid.NewV7()
+			repository.Create(Tests.TestCaseTenantModel{Id: id, Value: value + " " + tenant})
+		}
+	}
+}
+
+func Test_creating_entries_assigns_the_tenant(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	repository := Repository.Of[Tests.TestCaseTenantModel](tenantConfig("tenant-a"))
+	id, _ := uuid.NewV7()
+
+	// Act
+	entry := repository.Create(Tests.TestCaseTenantModel{Id: id, Value: "Value [NEW]"})
+
+	// Assert
+	assert.Equal(t, "tenant-a", entry.TenantId)
+	assert.Equal(t, "tenant-a", Repository.Of[Tests.TestCaseTenantModel]().WithoutTenancy().First().TenantId)
+}
+
+func Test_reads_are_scoped_to_the_tenant(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+	seedTenants()
+
+	repository := Repository.Of[Tests.TestCaseTenantModel](tenantConfig("tenant-b"))
+
+	// Act
+	all := repository.All()
+	first := repository.First()
+	queried := repository.GormQuery(func(query *gorm.DB) *gorm.DB {
+		return query.Where("value LIKE ?", "Value [1]%")
+	})
+	built := repository.Query().Get()
+	exists := repository.Query().Where("value", "Value [1] tenant-a").Exists()
+
+	// Assert
+	assert.Equal(t, 2, all.Count())
+	assert.Equal(t, "tenant-b", first.TenantId)
+	assert.Equal(t, 1, queried.Count())
+	assert.Equal(t, "Value [1] tenant-b", queried.First().Value)
+	assert.Equal(t, 2, built.Count())
+	assert.False(t, exists)
+}
+
+func Test_updates_and_deletes_are_scoped_to_the_tenant(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+	seedTenants()
+
+	other := Repository.Of[Tests.TestCaseTenantModel](tenantConfig("tenant-a")).First()
+	repository := Repository.Of[Tests.TestCaseTenantModel](tenantConfig("tenant-b"))
+
+	// Act
+	err := repository.Update(other.Id, map[string]any{"value": "Value [HIJACKED]"})
+	repository.Query().Where("value LIKE ?", "Value [1]%").Delete()
+
+	// Assert
+	assert.NotNil(t, err)
+	assert.Equal(t, "Value [1] tenant-a", Repository.Of[Tests.TestCaseTenantModel](tenantConfig("tenant-a")).First().Value)
+	assert.Equal(t, 2, Repository.Of[Tests.TestCaseTenantModel](tenantConfig("tenant-a")).All().Count())
+	assert.Equal(t, 1, repository.All().Count())
+}
+
+func Test_the_tenant_can_be_carried_in_a_context(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+	seedTenants()
+
+	ctx := Repository.TenantContext(context.Background(), "tenant-a")
+
+	// Act
+	entries := Repository.Of[Tests.TestCaseTenantModel]().WithContext(ctx).All()
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "tenant-a", entries.First().TenantId)
+}
+
+func Test_tenant_scoped_models_cannot_be_queried_without_a_tenant(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+	seedTenants()
+
+	repository := Repository.Of[Tests.TestCaseTenantModel]()
+	id, _ := uuid.NewV7()
+
+	// Act
+	err := repository.Update(id, map[string]any{"value": "Value [UPDATED]"})
+
+	// Assert
+	assert.True(t, errors.Is(err, Repository.ErrMissingTenant))
+	assert.Panics(t, func() { repository.All() })
+	assert.Panics(t, func() { repository.Query() })
+	assert.Panics(t, func() { repository.Create(Tests.TestCaseTenantModel{Id: id}) })
+	assert.Equal(t, 4, repository.WithoutTenancy().All().Count())
+}
+
+func Test_models_without_a_tenant_column_are_shared_between_tenants(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	// Act
+	entries := Repository.Of[Tests.TestCaseModel](tenantConfig("tenant-a")).All()
+
+	// Assert
+	assert.Equal(t, 5, entries.Count())
+}
+
+func Test_transactions_keep_the_tenant_scope(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+	seedTenants()
+
+	var count int
+
+	// Act
+	err := Repository.Transaction(func(config Repository.Config) error {
+		count = Repository.Of[Tests.TestCaseTenantModel](config).All().Count()
+
+		return nil
+	}, tenantConfig("tenant-a"))
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func Test_tenants_can_be_kept_apart_by_table_prefix(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	config := tenantConfig("tenant_a", Repository.TenancyTablePrefix)
+	migrateTenantTables(config, "tenant_a", "tenant_b")
+
+	repository := Repository.Of[Tests.TestCaseModel](config)
+	id, _ := uuid.NewV7()
+
+	// Act
+	repository.Create(Tests.TestCaseModel{Id: id, Value: "Value [NEW]"})
+	err := repository.Update(id, map[string]any{"value": "Value [UPDATED]"})
+
+	// Assert
+	var prefixedCount, otherCount, sharedCount int64
+	config.DatabaseConnection.Table("tenant_a_test_case_models").Count(&prefixedCount)
+	config.DatabaseConnection.Table("tenant_b_test_case_models").Count(&otherCount)
+	config.DatabaseConnection.Table("test_case_models").Count(&sharedCount)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), prefixedCount)
+	assert.Equal(t, int64(0), otherCount)
+	assert.Equal(t, int64(0), sharedCount)
+	assert.Equal(t, "Value [UPDATED]", repository.Query().First().Value)
+	assert.Equal(t, 0, Repository.Of[Tests.TestCaseModel](tenantConfig("tenant_b", Repository.TenancyTablePrefix)).All().Count())
+}
+
+func Test_tenants_naming_tables_must_be_plain_identifiers(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	prefixed := Repository.Of[Tests.TestCaseModel](tenantConfig("x test_case_models --", Repository.TenancyTablePrefix))
+	schema := Repository.Of[Tests.TestCaseModel](tenantConfig("x; DROP TABLE test_case_models", Repository.TenancySchema))
+
+	// Act & Assert
+	assert.PanicsWithValue(t, "Repository[Query]: Repository: Invalid tenant \"x test_case_models --\"", func() {
+		prefixed.Query().Get()
+	})
+
+	assert.PanicsWithValue(t, "Repository[All]: Repository: Invalid tenant \"x; DROP TABLE test_case_models\"", func() {
+		schema.All()
+	})
+
+	err := prefixed.Update(uuid.New(), map[string]any{"value": "Value [UPDATED]"})
+
+	assert.True(t, errors.Is(err, Repository.ErrInvalidTenant))
+	assert.Equal(t, 5, Repository.Of[Tests.TestCaseModel]().All().Count())
+}
+
+func Test_tenant_tables_are_quoted(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	prefixed := Repository.Of[Tests.TestCaseModel](tenantConfig("tenant_a", Repository.TenancyTablePrefix))
+	schema := Repository.Of[Tests.TestCaseModel](tenantConfig("tenant_a", Repository.TenancySchema))
+
+	// Act
+	prefixedSQL := prefixed.Query().Where("value = ?", "Value [1]").ToRawSQL()
+	schemaSQL := schema.Query().ToRawSQL()
+
+	// Assert
+	assert.Equal(t, "SELECT * FROM `tenant_a_test_case_models` WHERE value = \"Value [1]\"", prefixedSQL)
+	assert.Equal(t, "SELECT * FROM `tenant_a`.`test_case_models`", schemaSQL)
+}
+
+func Test_entries_are_created_in_the_schema_of_the_tenant(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config := tenantConfig("tenant_a", Repository.TenancySchema)
+	attachTenantSchema(config, "tenant_a")
+
+	repository := Repository.Of[Tests.TestCaseModel](config)
+	id, _ := uuid.NewV7()
+
+	// Act
+	repository.Create(Tests.TestCaseModel{Id: id, Value: "Value [TENANT]"})
+
+	// Assert
+	assert.Equal(t, 1, repository.All().Count())
+	assert.Equal(t, 5, Repository.Of[Tests.TestCaseModel]().All().Count())
+}
+
+func Test_preloaded_and_joined_relations_are_read_from_the_tables_of_the_tenant(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	config := tenantConfig("tenant_a", Repository.TenancyTablePrefix)
+	migrateTenantTables(config, "tenant_a")
+
+	id, _ := uuid.NewV7()
+	Repository.Of[Tests.TestCaseModel](config).Create(Tests.TestCaseModel{Id: id, Value: "Value [TENANT]"})
+	Repository.Of[Tests.TestCaseRelationModel](config).Create(Tests.TestCaseRelationModel{Id: uuid.New(), TestCaseModelId: id, Value: "Relation Value [TENANT]"})
+	Repository.Of[Tests.TestCaseRelationModel]().Create(Tests.TestCaseRelationModel{Id: uuid.New(), TestCaseModelId: id, Value: "Relation Value [SHARED]"})
+
+	repository := Repository.Of[Tests.TestCaseModel](config)
+
+	// Act
+	entries := repository.All()
+	joined := repository.Query().JoinRelation("TestCaseRelationModels").Get()
+	relations := Repository.Of[Tests.TestCaseRelationModel](config).Query().With("TestCaseModel.TestCaseRelationModels").Get()
+	sql := repository.Query().JoinRelation("TestCaseRelationModels").ToRawSQL()
+
+	// Assert
+	assert.Equal(t, 1, entries.Count())
+	assert.Len(t, entries.First().TestCaseRelationModels, 1)
+	assert.Equal(t, "Relation Value [TENANT]", entries.First().TestCaseRelationModels[0].Value)
+	assert.Equal(t, 1, joined.Count())
+	assert.Equal(t, "Value [TENANT]", relations.First().TestCaseModel.Value)
+	assert.Len(t, relations.First().TestCaseModel.TestCaseRelationModels, 1)
+	assert.Contains(t, sql, "JOIN `tenant_a_test_case_relation_models` ON")
+	assert.NotContains(t, sql, "JOIN `test_case_relation_models`")
+}
+
+func Test_preloaded_and_joined_relations_with_a_tenant_column_are_scoped_to_the_tenant(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	id, _ := uuid.NewV7()
+	Repository.Of[Tests.TestCaseTenantModel](tenantConfig("tenant-a")).Create(Tests.TestCaseTenantModel{Id: id, Value: "Value [1]"})
+
+	for _, tenant := range []string{"tenant-a", "tenant-b"} {
+		Repository.Of[Tests.TestCaseTenantNoteModel](tenantConfig(tenant)).Create(Tests.TestCaseTenantNoteModel{
+			Id:                    uuid.New(),
+			TestCaseTenantModelId: id,
+			Value:                 "Note " + tenant,
+		})
+	}
+
+	repository := Repository.Of[Tests.TestCaseTenantModel](tenantConfig("tenant-a"))
+
+	// Act
+	preloaded := repository.Query().With("Notes").First()
+	joined := repository.Query().JoinRelation("Notes").Get()
+
+	// Assert
+	assert.Len(t, preloaded.Notes, 1)
+	assert.Equal(t, "Note tenant-a", preloaded.Notes[0].Value)
+	assert.Equal(t, 1, joined.Count())
+	assert.Equal(t, 2, Repository.Of[Tests.TestCaseTenantModel]().WithoutTenancy().Query().JoinRelation("Notes").Get().Count())
+}
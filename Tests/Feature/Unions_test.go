@@ -0,0 +1,90 @@
+package Feature
+
+import (
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_query_builder_can_union_queries(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		Where("value = ?", "Value [1]").
+		Union(repository.Query().Where("value IN ?", []string{"Value [1]", "Value [4]"})).
+		OrderBy("value", "asc").
+		Get()
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "Value [1]", entries.First().Value)
+	assert.Equal(t, "Value [4]", entries.Last().Value)
+}
+
+func Test_query_builder_can_union_all_queries_keeping_duplicates(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		Where("value = ?", "Value [1]").
+		UnionAll(repository.Query().Where("value IN ?", []string{"Value [1]", "Value [4]"})).
+		Get()
+
+	// Assert
+	assert.Equal(t, 3, entries.Count())
+}
+
+func Test_ordering_and_limits_apply_to_the_combined_results_of_unions(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	entries := repository.Query().
+		Where("value IN ?", []string{"Value [1]", "Value [2]"}).
+		Union(repository.Query().Where("value IN ?", []string{"Value [4]", "Value [5]"})).
+		OrderBy("value", "desc").
+		Take(3).
+		Get()
+
+	first := repository.Query().
+		Where("value = ?", "Value [2]").
+		Union(repository.Query().Where("value = ?", "Value [5]")).
+		OrderBy("value", "desc").
+		First()
+
+	// Assert
+	assert.Equal(t, 3, entries.Count())
+	assert.Equal(t, "Value [5]", entries.First().Value)
+	assert.Equal(t, "Value [2]", entries.Last().Value)
+	assert.Equal(t, "Value [5]", first.Value)
+}
+
+func Test_unions_can_be_paginated(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act
+	paginator := repository.Query().
+		Where("value IN ?", []string{"Value [1]", "Value [2]"}).
+		Union(repository.Query().Where("value IN ?", []string{"Value [3]", "Value [4]"})).
+		OrderBy("value", "asc").
+		Paginate(1, 3, "/")
+
+	// Assert
+	assert.Equal(t, 4, paginator.Total)
+	assert.Equal(t, 2, paginator.LastPage)
+	assert.Equal(t, 3, paginator.Items.Count())
+	assert.Equal(t, "Value [1]", paginator.Items.First().Value)
+}
@@ -0,0 +1,262 @@
+package Feature
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// recordWrites
+// Records the tables written to, in order, on the default connection
+func recordWrites() *[]string {
+	var tables []string
+
+	config, _ := Repository.Connection(Repository.DefaultConnection)
+	record := func(db *gorm.DB) {
+		tables = append(tables, db.Statement.Schema.Table)
+	}
+
+	config.DatabaseConnection.Callback().Create().After("gorm:create").Register("tests:record_create", record)
+	config.DatabaseConnection.Callback().Delete().After("gorm:delete").Register("tests:record_delete", record)
+
+	return &tables
+}
+
+func Test_a_unit_of_work_keeps_one_instance_per_entity(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	unit := Repository.NewUnitOfWork()
+	first := Repository.Of[Tests.TestCaseModel]().Query().Where("value", "Value [1]").First()
+
+	// Act
+	tracked := Repository.Track(unit, first)
+	loaded := Repository.Load[Tests.TestCaseModel](unit, first.Id)
+	reloaded := Repository.Track(unit, Repository.Of[Tests.TestCaseModel]().Query().Where("value", "Value [1]").First())
+
+	// Assert
+	assert.Same(t, first, tracked)
+	assert.Same(t, first, loaded)
+	assert.Same(t, first, reloaded)
+	assert.True(t, unit.IsTracked(first))
+}
+
+func Test_a_unit_of_work_loads_untracked_entities_from_the_database(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	unit := Repository.NewUnitOfWork()
+	id := Repository.Of[Tests.TestCaseModel]().Query().Where("value", "Value [2]").First().Id
+	missing, _ := uuid.NewV7()
+
+	// Act
+	loaded := Repository.Load[Tests.TestCaseModel](unit, id)
+
+	// Assert
+	assert.Equal(t, "Value [2]", loaded.Value)
+	assert.True(t, unit.IsTracked(loaded))
+	assert.Nil(t, Repository.Load[Tests.TestCaseModel](unit, missing))
+}
+
+func Test_a_unit_of_work_flushes_changes_on_commit(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	unit := Repository.NewUnitOfWork()
+	entries := Repository.TrackAll(unit, Repository.Of[Tests.TestCaseModel]().All().All())
+
+	entries[0].Value = "Value [CHANGED]"
+
+	// Act
+	err := unit.Commit()
+
+	// Assert
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	assert.Nil(t, err)
+	assert.True(t, repository.Query().Where("value", "Value [CHANGED]").Exists())
+	assert.Equal(t, 4, repository.Query().Where("value LIKE ?", "Value [_]").Get().Count())
+}
+
+func Test_a_unit_of_work_increments_versions_of_versioned_models(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	id, _ := uuid.NewV7()
+	Repository.Of[Tests.TestCaseVersionedModel]().Create(Tests.TestCaseVersionedModel{Id: id, Value: "Value [NEW]"})
+
+	unit := Repository.NewUnitOfWork()
+	model := Repository.Load[Tests.TestCaseVersionedModel](unit, id)
+
+	// Act
+	model.Value = "Value [FIRST]"
+	errA := unit.Commit()
+
+	model.Value = "Value [SECOND]"
+	errB := unit.Commit()
+
+	// Assert
+	assert.Nil(t, errA)
+	assert.Nil(t, errB)
+	assert.Equal(t, 3, model.Version)
+	assert.Equal(t, 3, Repository.Of[Tests.TestCaseVersionedModel]().First().Version)
+}
+
+func Test_a_unit_of_work_inserts_and_deletes_in_dependency_order(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+	writes := recordWrites()
+
+	unit := Repository.NewUnitOfWork()
+	parentId, _ := uuid.NewV7()
+	childId, _ := uuid.NewV7()
+
+	child := Repository.Insert(unit, &Tests.TestCaseRelationModel{Id: childId, TestCaseModelId: parentId})
+	parent := Repository.Insert(unit, &Tests.TestCaseModel{Id: parentId, Value: "Value [PARENT]"})
+
+	// Act
+	errInsert := unit.Commit()
+
+	assert.Nil(t, Repository.Delete(unit, parent))
+	assert.Nil(t, Repository.Delete(unit, child))
+	errDelete := unit.Commit()
+
+	// Assert
+	assert.Nil(t, errInsert)
+	assert.Nil(t, errDelete)
+	assert.Equal(t, []string{
+		"test_case_models",
+		"test_case_relation_models",
+		"test_case_relation_models",
+		"test_case_models",
+	}, *writes)
+	assert.False(t, unit.IsTracked(parent))
+	assert.Equal(t, 0, Repository.Of[Tests.TestCaseModel]().All().Count())
+}
+
+func Test_a_unit_of_work_rolls_back_everything_if_anything_fails(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	versionedId, _ := uuid.NewV7()
+	Repository.Of[Tests.TestCaseVersionedModel]().Create(Tests.TestCaseVersionedModel{Id: versionedId, Value: "Value [NEW]"})
+
+	unit := Repository.NewUnitOfWork()
+	versioned := Repository.Load[Tests.TestCaseVersionedModel](unit, versionedId)
+
+	insertedId, _ := uuid.NewV7()
+	Repository.Insert(unit, &Tests.TestCaseModel{Id: insertedId, Value: "Value [INSERTED]"})
+
+	// Another editor gets there first
+	Repository.Of[Tests.TestCaseVersionedModel]().Update(versionedId, map[string]any{
+		"value":   "Value [OTHER EDITOR]",
+		"version": 1,
+	})
+
+	versioned.Value = "Value [CHANGED]"
+
+	// Act
+	err := unit.Commit()
+
+	// Assert
+	assert.True(t, errors.Is(err, Repository.ErrStaleModel))
+	assert.Equal(t, 0, Repository.Of[Tests.TestCaseModel]().All().Count())
+	assert.Equal(t, "Value [OTHER EDITOR]", Repository.Of[Tests.TestCaseVersionedModel]().First().Value)
+}
+
+func Test_deleting_an_untracked_model_returns_an_error(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	unit := Repository.NewUnitOfWork()
+
+	// Act
+	err := Repository.Delete(unit, Repository.Of[Tests.TestCaseModel]().First())
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+func Test_a_unit_of_work_can_be_committed_again_after_failing(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	firstId, _ := uuid.NewV7()
+	secondId, _ := uuid.NewV7()
+	Repository.Of[Tests.TestCaseVersionedModel]().Create(Tests.TestCaseVersionedModel{Id: firstId, Value: "Value [FIRST]"})
+	Repository.Of[Tests.TestCaseVersionedModel]().Create(Tests.TestCaseVersionedModel{Id: secondId, Value: "Value [SECOND]"})
+
+	unit := Repository.NewUnitOfWork()
+	first := Repository.Load[Tests.TestCaseVersionedModel](unit, firstId)
+	second := Repository.Load[Tests.TestCaseVersionedModel](unit, secondId)
+
+	// Another editor gets to the second model first
+	Repository.Of[Tests.TestCaseVersionedModel]().Update(secondId, map[string]any{
+		"value":   "Value [OTHER EDITOR]",
+		"version": 1,
+	})
+
+	first.Value = "Value [FIRST CHANGED]"
+	second.Value = "Value [SECOND CHANGED]"
+
+	// Act
+	failed := unit.Commit()
+
+	second.Version = 2
+	retried := unit.Commit()
+
+	// Assert
+	assert.True(t, errors.Is(failed, Repository.ErrStaleModel))
+	assert.Nil(t, retried)
+	assert.Equal(t, 2, first.Version)
+	assert.Equal(t, 3, second.Version)
+	assert.Equal(t, "Value [FIRST CHANGED]", Repository.Of[Tests.TestCaseVersionedModel]().Query().Where("id = ?", firstId).First().Value)
+	assert.Equal(t, "Value [SECOND CHANGED]", Repository.Of[Tests.TestCaseVersionedModel]().Query().Where("id = ?", secondId).First().Value)
+}
+
+func Test_a_unit_of_work_writes_models_to_the_connection_they_declare(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+	connection := Tests.SetupConnection("analytics")
+
+	unit := Repository.NewUnitOfWork()
+	id, _ := uuid.NewV7()
+	Repository.Insert(unit, &Tests.TestCaseAnalyticsModel{Id: id, Value: "Value [NEW]"})
+
+	// Act
+	err := unit.Commit()
+
+	// Assert
+	var count int64
+	connection.Model(&Tests.TestCaseAnalyticsModel{}).Count(&count)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func Test_a_unit_of_work_spanning_connections_cannot_be_committed(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+	connection := Tests.SetupConnection("analytics")
+
+	unit := Repository.NewUnitOfWork()
+	analyticsId, _ := uuid.NewV7()
+	modelId, _ := uuid.NewV7()
+	Repository.Insert(unit, &Tests.TestCaseAnalyticsModel{Id: analyticsId, Value: "Value [NEW]"})
+	Repository.Insert(unit, &Tests.TestCaseModel{Id: modelId, Value: "Value [NEW]"})
+
+	// Act
+	err := unit.Commit()
+
+	// Assert
+	var count int64
+	connection.Model(&Tests.TestCaseAnalyticsModel{}).Count(&count)
+
+	assert.True(t, errors.Is(err, Repository.ErrUnitSpansConnections))
+	assert.Equal(t, int64(0), count)
+	assert.Equal(t, 0, Repository.Of[Tests.TestCaseModel]().All().Count())
+}
@@ -0,0 +1,109 @@
+package Feature
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_versioned_models_are_created_with_version_one(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	repository := Repository.Of[Tests.TestCaseVersionedModel]()
+	id, _ := uuid.NewV7()
+
+	// Act
+	model := repository.Create(Tests.TestCaseVersionedModel{
+		Id:    id,
+		Value: "Value [NEW]",
+	})
+
+	// Assert
+	assert.Equal(t, 1, model.Version)
+	assert.Equal(t, 1, repository.All().First().Version)
+}
+
+func Test_updating_a_versioned_model_increments_its_version(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	repository := Repository.Of[Tests.TestCaseVersionedModel]()
+	id, _ := uuid.NewV7()
+
+	model := repository.Create(Tests.TestCaseVersionedModel{
+		Id:    id,
+		Value: "Value [NEW]",
+	})
+
+	// Act
+	errA := repository.Update(model.Id, map[string]any{
+		"value":   "Value [UPDATED]",
+		"version": model.Version,
+	})
+
+	model.Value = "Value [UPDATED AGAIN]"
+	model.Version = 2
+	errB := repository.Update(model.Id, model)
+
+	// Assert
+	assert.Nil(t, errA)
+	assert.Nil(t, errB)
+	assert.Equal(t, "Value [UPDATED AGAIN]", repository.All().First().Value)
+	assert.Equal(t, 3, repository.All().First().Version)
+}
+
+func Test_updating_a_stale_versioned_model_returns_an_error(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	repository := Repository.Of[Tests.TestCaseVersionedModel]()
+	id, _ := uuid.NewV7()
+
+	model := repository.Create(Tests.TestCaseVersionedModel{
+		Id:    id,
+		Value: "Value [NEW]",
+	})
+
+	assert.Nil(t, repository.Update(model.Id, map[string]any{
+		"value":   "Value [FIRST EDITOR]",
+		"version": model.Version,
+	}))
+
+	// Act
+	err := repository.Update(model.Id, map[string]any{
+		"value":   "Value [SECOND EDITOR]",
+		"version": model.Version,
+	})
+
+	// Assert
+	assert.True(t, errors.Is(err, Repository.ErrStaleModel))
+	assert.Equal(t, Repository.ErrStaleModel, repository.GetLatestError())
+	assert.Equal(t, "Value [FIRST EDITOR]", repository.All().First().Value)
+	assert.Equal(t, 2, repository.All().First().Version)
+}
+
+func Test_updating_a_versioned_model_without_a_version_returns_an_error(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment(true)
+
+	repository := Repository.Of[Tests.TestCaseVersionedModel]()
+	id, _ := uuid.NewV7()
+
+	model := repository.Create(Tests.TestCaseVersionedModel{
+		Id:    id,
+		Value: "Value [NEW]",
+	})
+
+	// Act
+	err := repository.Update(model.Id, map[string]any{
+		"value": "Value [UPDATED]",
+	})
+
+	// Assert
+	assert.True(t, errors.Is(err, Repository.ErrMissingVersion))
+	assert.Equal(t, "Value [NEW]", repository.All().First().Value)
+}
@@ -0,0 +1,146 @@
+package Feature
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"github.com/nbj/go-repository/Tests"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testCaseRankedModel struct {
+	Value    string
+	Position int
+	Previous *string
+	Next     *string
+}
+
+type testCaseRunningTotalModel struct {
+	Value   string
+	Version int
+	Total   int
+}
+
+func Test_query_builder_can_select_window_functions(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseTreeModel]()
+	seedTree(repository)
+
+	// Act
+	entries := Repository.Project[testCaseRankedModel](repository.Query().
+		Select("value").
+		SelectWindow("row_number", "parent_id", "value desc", "position").
+		Lag("value", "parent_id", "value", "previous").
+		Lead("value", "parent_id", "value", "next").
+		Where("parent_id IS NOT NULL").
+		OrderBy("value", "asc"))
+
+	// Assert
+	assert.Equal(t, 4, entries.Count())
+
+	branchA, branchB, leafA1, leafA2 := entries.Get(0), entries.Get(1), entries.Get(2), entries.Get(3)
+
+	assert.Equal(t, "Branch [A]", branchA.Value)
+	assert.Equal(t, 2, branchA.Position)
+	assert.Nil(t, branchA.Previous)
+	assert.Equal(t, "Branch [B]", *branchA.Next)
+	assert.Equal(t, 1, branchB.Position)
+	assert.Equal(t, "Branch [A]", *branchB.Previous)
+	assert.Nil(t, branchB.Next)
+	assert.Equal(t, 2, leafA1.Position)
+	assert.Equal(t, 1, leafA2.Position)
+	assert.Equal(t, "Leaf [A1]", *leafA2.Previous)
+}
+
+func Test_query_builder_can_rank_rows(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseVersionedModel]()
+
+	for index, version := range []int{3, 1, 3, 2} {
+		id, _ := uuid.NewV7()
+		repository.Create(Tests.TestCaseVersionedModel{Id: id, Value: string(rune('A' + index)), Version: version})
+	}
+
+	// Act
+	entries := Repository.Project[testCaseRankedModel](repository.Query().
+		Select("value").
+		Rank("", "version desc", "position").
+		OrderBy("value", "asc"))
+
+	// Assert
+	assert.Equal(t, []int{1, 4, 1, 3}, []int{
+		entries.Get(0).Position,
+		entries.Get(1).Position,
+		entries.Get(2).Position,
+		entries.Get(3).Position,
+	})
+}
+
+func Test_query_builder_can_sum_over_rows(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseVersionedModel]()
+
+	for index, version := range []int{1, 2, 3, 4} {
+		id, _ := uuid.NewV7()
+		repository.Create(Tests.TestCaseVersionedModel{Id: id, Value: string(rune('A' + index)), Version: version})
+	}
+
+	// Act
+	running := Repository.Project[testCaseRunningTotalModel](repository.Query().
+		Select("value", "version").
+		SumOver("version", "", "value", "total").
+		OrderBy("value", "asc"))
+
+	total := Repository.Project[testCaseRunningTotalModel](repository.Query().
+		Select("value", "version").
+		SumOver("version", "", "", "total"))
+
+	// Assert
+	assert.Equal(t, []int{1, 3, 6, 10}, []int{
+		running.Get(0).Total,
+		running.Get(1).Total,
+		running.Get(2).Total,
+		running.Get(3).Total,
+	})
+	assert.Equal(t, 10, total.First().Total)
+}
+
+func Test_query_builder_can_get_the_latest_row_per_group(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseTreeModel]()
+	seedTree(repository)
+
+	// Act
+	query := repository.Query().
+		Where("parent_id IS NOT NULL").
+		LatestPerGroup("parent_id", "value").
+		OrderBy("value", "asc")
+
+	entries := query.Get()
+	paginator := query.Paginate(1, 1, "/")
+
+	// Assert
+	assert.Equal(t, 2, entries.Count())
+	assert.Equal(t, "Branch [B]", entries.First().Value)
+	assert.Equal(t, "Leaf [A2]", entries.Last().Value)
+	assert.Equal(t, 2, paginator.Total)
+}
+
+func Test_window_functions_reject_unsupported_input(t *testing.T) {
+	// Arrange
+	Tests.SetupEnvironment()
+
+	repository := Repository.Of[Tests.TestCaseModel]()
+
+	// Act & Assert
+	assert.Panics(t, func() { repository.Query().SelectWindow("drop_table", "", "value", "position") })
+	assert.Panics(t, func() { repository.Query().Rank("", "value sideways", "position") })
+}
@@ -3,6 +3,7 @@ package Tests
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Queue"
 	"github.com/nbj/go-repository/Repository"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -12,12 +13,49 @@ func SetupEnvironment(noSeed ...bool) {
 	connection := getSqliteDatabaseConnection()
 
 	if len(noSeed) == 0 {
-		seedTestData(connection)
+		SeedTestData(connection)
 	}
 
 	Repository.SetDefaultConfig(&Repository.Config{DatabaseConnection: connection})
 }
 
+// SetupConnection
+// Registers an empty named connection next to the default one
+func SetupConnection(name string) *gorm.DB {
+	connection := getSqliteDatabaseConnection()
+
+	Repository.AddConnection(name, Repository.Config{DatabaseConnection: connection})
+
+	return connection
+}
+
+// SetupReplicatedEnvironment
+// Sets up an empty primary connection and a number of seeded
+// replicas, making it visible which connection was queried
+func SetupReplicatedEnvironment(numberOfReaders int, policy ...Repository.ReadPolicy) (*gorm.DB, []*gorm.DB) {
+	writer := getSqliteDatabaseConnection()
+	var readers []*gorm.DB
+
+	for reader := 1; reader <= numberOfReaders; reader++ {
+		connection := getSqliteDatabaseConnection()
+		SeedTestData(connection)
+		readers = append(readers, connection)
+	}
+
+	config := Repository.Config{
+		DatabaseConnection: writer,
+		ReadConnections:    readers,
+	}
+
+	if len(policy) > 0 {
+		config.ReadPolicy = policy[0]
+	}
+
+	Repository.SetDefaultConfig(&config)
+
+	return writer, readers
+}
+
 func getSqliteDatabaseConnection() *gorm.DB {
 	var connection *gorm.DB
 	var err error
@@ -31,6 +69,14 @@ func getSqliteDatabaseConnection() *gorm.DB {
 	modelsToMigrate := []any{
 		TestCaseModel{},
 		TestCaseRelationModel{},
+		TestCaseVersionedModel{},
+		TestCaseAnalyticsModel{},
+		TestCaseTenantModel{},
+		TestCaseTenantNoteModel{},
+		TestCaseTreeModel{},
+		TestCaseDocumentModel{},
+		TestCaseAccountModel{},
+		Queue.Job{},
 	}
 
 	if err = connection.AutoMigrate(modelsToMigrate...); nil != err {
@@ -40,7 +86,7 @@ func getSqliteDatabaseConnection() *gorm.DB {
 	return connection
 }
 
-func seedTestData(connection *gorm.DB) {
+func SeedTestData(connection *gorm.DB) {
 	numberOfEntries := 5
 	var instances []*TestCaseModel
 
@@ -78,3 +124,23 @@ func makeTestCaseRelationModel(testCaseModelId uuid.UUID, value string) TestCase
 
 	return instance
 }
+
+// SeedSharedRelation
+// Moves "Relation Value [1]" onto the model of the first relation, so
+// that model has two relations and the others one each. Returns the id
+// of the model sharing relations. Panics if the relation cannot be moved
+func SeedSharedRelation() uuid.UUID {
+	repository := Repository.Of[TestCaseRelationModel]()
+	first := repository.First()
+	moved := repository.Query().Where("value", "Relation Value [1]").FirstOrFail()
+
+	err := repository.Update(moved.Id, map[string]any{
+		"test_case_model_id": first.TestCaseModelId,
+	})
+
+	if err != nil {
+		panic("Tests[SeedSharedRelation]: " + err.Error())
+	}
+
+	return first.TestCaseModelId
+}
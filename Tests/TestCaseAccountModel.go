@@ -0,0 +1,14 @@
+package Tests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TestCaseAccountModel struct {
+	Id        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;uniqueIndex"`
+	Email     string    `json:"email"`
+	Password  string    `json:"-" repository:"redact"`
+	CreatedAt time.Time `json:"created_at" gorm:"index;not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+}
@@ -0,0 +1,17 @@
+package Tests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TestCaseAnalyticsModel struct {
+	Id        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;uniqueIndex"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at" gorm:"index;not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+}
+
+func (model *TestCaseAnalyticsModel) Connection() string {
+	return "analytics"
+}
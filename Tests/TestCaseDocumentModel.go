@@ -0,0 +1,25 @@
+package Tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/nbj/go-repository/Repository"
+	"time"
+)
+
+type TestCaseDocumentAddress struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type TestCaseDocumentMeta struct {
+	Address TestCaseDocumentAddress `json:"address"`
+	Score   int                     `json:"score"`
+	Tags    []string                `json:"tags"`
+}
+
+type TestCaseDocumentModel struct {
+	Id        uuid.UUID                             `json:"id" gorm:"type:uuid;primaryKey;uniqueIndex"`
+	Meta      Repository.JSON[TestCaseDocumentMeta] `json:"meta"`
+	CreatedAt time.Time                             `json:"created_at" gorm:"index;not null"`
+	UpdatedAt time.Time                             `json:"updated_at" gorm:"not null"`
+}
@@ -0,0 +1,15 @@
+package Tests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TestCaseTenantModel struct {
+	Id        uuid.UUID                  `json:"id" gorm:"type:uuid;primaryKey;uniqueIndex"`
+	TenantId  string                     `json:"tenant_id" gorm:"index;not null" repository:"tenant"`
+	Value     string                     `json:"value"`
+	Notes     []*TestCaseTenantNoteModel `json:"notes"`
+	CreatedAt time.Time                  `json:"created_at" gorm:"index;not null"`
+	UpdatedAt time.Time                  `json:"updated_at" gorm:"not null"`
+}
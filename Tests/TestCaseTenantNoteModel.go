@@ -0,0 +1,15 @@
+package Tests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TestCaseTenantNoteModel struct {
+	Id                    uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;uniqueIndex"`
+	TestCaseTenantModelId uuid.UUID `json:"test_case_tenant_model_id" gorm:"type:uuid"`
+	TenantId              string    `json:"tenant_id" gorm:"index;not null" repository:"tenant"`
+	Value                 string    `json:"value"`
+	CreatedAt             time.Time `json:"created_at" gorm:"index;not null"`
+	UpdatedAt             time.Time `json:"updated_at" gorm:"not null"`
+}
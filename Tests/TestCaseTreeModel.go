@@ -0,0 +1,14 @@
+package Tests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TestCaseTreeModel struct {
+	Id        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;uniqueIndex"`
+	ParentId  *uuid.UUID `json:"parent_id" gorm:"type:uuid;index" repository:"parent"`
+	Value     string     `json:"value"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index;not null"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"not null"`
+}
@@ -0,0 +1,14 @@
+package Tests
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TestCaseVersionedModel struct {
+	Id        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;uniqueIndex"`
+	Value     string    `json:"value"`
+	Version   int       `json:"version" repository:"version"`
+	CreatedAt time.Time `json:"created_at" gorm:"index;not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+}